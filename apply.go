@@ -0,0 +1,224 @@
+package needle
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/danpasecinic/needle/internal/container"
+)
+
+type applyTx struct {
+	c            *Container
+	existing     map[string]bool
+	defaults     []replacedDefault
+	inactive     int
+	applied      map[*Module]bool
+	names        map[string]bool
+	requirements []moduleRequirement
+	decorators   []func()
+	hooks        []string
+}
+
+type replacedDefault struct {
+	checkpoint container.Checkpoint
+	rule       [][]string
+	hasRule    bool
+	runner     runnerSpec
+	hasRunner  bool
+}
+
+type moduleRequirement struct {
+	module string
+	key    string
+	target *Module
+}
+
+func (c *Container) beginApply() *applyTx {
+	existing := make(map[string]bool)
+	var defaults []replacedDefault
+	for _, entry := range c.internal.Entries() {
+		existing[entry.Key] = true
+		if !entry.Default {
+			continue
+		}
+		if checkpoint, ok := c.internal.Checkpoint(entry.Key); ok {
+			defaults = append(defaults, replacedDefault{checkpoint: checkpoint})
+		}
+	}
+
+	c.profiles.mu.RLock()
+	inactive := len(c.profiles.inactive)
+	for i := range defaults {
+		defaults[i].rule, defaults[i].hasRule = c.profiles.rules[defaults[i].checkpoint.Key()]
+	}
+	c.profiles.mu.RUnlock()
+
+	c.runners.mu.Lock()
+	for i := range defaults {
+		defaults[i].runner, defaults[i].hasRunner = c.runners.specs[defaults[i].checkpoint.Key()]
+	}
+	c.runners.mu.Unlock()
+
+	return &applyTx{
+		c:        c,
+		existing: existing,
+		defaults: defaults,
+		inactive: inactive,
+		applied:  make(map[*Module]bool),
+		names:    make(map[string]bool),
+	}
+}
+
+func (tx *applyTx) enter(c *Container, m *Module) bool {
+	id := m.identity()
+	if tx.applied[m] || (id != "" && tx.names[id]) || c.modules.isApplied(m) {
+		return false
+	}
+
+	tx.applied[m] = true
+	if id != "" {
+		tx.names[id] = true
+	}
+	return true
+}
+
+func (tx *applyTx) require(path string, requirements []any) error {
+	for _, r := range requirements {
+		switch v := r.(type) {
+		case *Module:
+			tx.requirements = append(tx.requirements, moduleRequirement{module: path, target: v})
+		case string:
+			tx.requirements = append(tx.requirements, moduleRequirement{module: path, key: v})
+		default:
+			return fmt.Errorf("invalid requirement %T: expected *Module or key", r)
+		}
+	}
+	return nil
+}
+
+func (tx *applyTx) commit() {
+	c := tx.c
+
+	c.modules.mu.Lock()
+	for m := range tx.applied {
+		c.modules.applied[m] = true
+	}
+	for name := range tx.names {
+		c.modules.names[name] = true
+	}
+	c.modules.requirements = append(c.modules.requirements, tx.requirements...)
+	c.modules.mu.Unlock()
+
+	for _, decorate := range tx.decorators {
+		decorate()
+	}
+}
+
+func (tx *applyTx) rollback() {
+	c := tx.c
+
+	removed := make(map[string]bool)
+	for _, key := range c.internal.Keys() {
+		if !tx.existing[key] {
+			c.internal.Unregister(key)
+			c.internal.SetPhase(key, "")
+			c.internal.SetStartAfter(key, nil)
+			removed[key] = true
+		}
+	}
+
+	for _, path := range tx.hooks {
+		c.internal.RemoveModuleHooks(path)
+	}
+
+	var restored []replacedDefault
+	for _, d := range tx.defaults {
+		if c.internal.Restore(d.checkpoint) {
+			restored = append(restored, d)
+		}
+	}
+
+	c.modules.forget(removed, c.internal.Has)
+
+	c.profiles.mu.Lock()
+	c.profiles.inactive = c.profiles.inactive[:tx.inactive]
+	for key := range removed {
+		delete(c.profiles.rules, key)
+	}
+	for _, d := range restored {
+		delete(c.profiles.rules, d.checkpoint.Key())
+		if d.hasRule {
+			c.profiles.rules[d.checkpoint.Key()] = d.rule
+		}
+	}
+	c.profiles.mu.Unlock()
+
+	c.runners.mu.Lock()
+	for key := range c.runners.specs {
+		if !tx.existing[key] {
+			delete(c.runners.specs, key)
+		}
+	}
+	for _, d := range restored {
+		delete(c.runners.specs, d.checkpoint.Key())
+		if d.hasRunner {
+			c.runners.specs[d.checkpoint.Key()] = d.runner
+		}
+	}
+	c.runners.mu.Unlock()
+
+	c.config.logger.Debug("module apply rolled back", "services", len(removed), "restored", len(restored))
+}
+
+func (idx *moduleIndex) isApplied(m *Module) bool {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	id := m.identity()
+	return idx.applied[m] || (id != "" && idx.names[id])
+}
+
+func (idx *moduleIndex) forget(removed map[string]bool, registered func(string) bool) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	for key := range removed {
+		delete(idx.owners, key)
+	}
+
+	for key, hidden := range idx.private {
+		for qualified := range hidden {
+			if !registered(qualified) {
+				delete(hidden, qualified)
+			}
+		}
+		if len(hidden) == 0 {
+			delete(idx.private, key)
+		}
+	}
+}
+
+func (c *Container) validateRequirements() error {
+	c.modules.mu.RLock()
+	defer c.modules.mu.RUnlock()
+
+	var unmet []string
+	for _, req := range c.modules.requirements {
+		if req.target != nil {
+			id := req.target.identity()
+			if !c.modules.applied[req.target] && (id == "" || !c.modules.names[id]) {
+				unmet = append(unmet, fmt.Sprintf("module %s requires module %s", req.module, req.target.name))
+			}
+			continue
+		}
+		if !c.internal.Has(req.key) {
+			unmet = append(unmet, fmt.Sprintf("module %s requires %s", req.module, req.key))
+		}
+	}
+
+	if len(unmet) > 0 {
+		sort.Strings(unmet)
+		return fmt.Errorf("unmet module requirements: %v", unmet)
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+package needle_test
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/danpasecinic/needle"
+)
+
+func TestModuleDeduplication(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"shared module included twice", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			logging := needle.NewModule("logging")
+			needle.ModuleProvideValue(logging, &Logger{Prefix: "shared"})
+
+			users := needle.NewModule("users").Include(logging)
+			orders := needle.NewModule("orders").Include(logging)
+
+			if err := c.Apply(users, orders); err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+			if err := c.Apply(logging); err != nil {
+				t.Fatalf("re-applying module failed: %v", err)
+			}
+			if c.Size() != 1 {
+				t.Errorf("expected 1 service, got %d", c.Size())
+			}
+		},
+	)
+
+	t.Run(
+		"modules with the same name", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			first := needle.NewModule("config")
+			needle.ModuleProvideValue(first, &Config{Port: 1})
+			second := needle.NewModule("config")
+			needle.ModuleProvideValue(second, &Config{Port: 2})
+
+			if err := c.Apply(first, second); err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+			if got := needle.MustInvoke[*Config](c).Port; got != 1 {
+				t.Errorf("expected first module to win, got port %d", got)
+			}
+		},
+	)
+}
+
+func TestModuleRequires(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"reports missing keys", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			m := needle.NewModule("db")
+			needle.ModuleRequires[*Config](m)
+
+			if err := c.Apply(m); err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+
+			err := c.Validate()
+			if err == nil || !strings.Contains(err.Error(), "module db requires *github.com/danpasecinic/needle_test.Config") {
+				t.Fatalf("expected unmet requirement, got %v", err)
+			}
+
+			_ = needle.ProvideValue(c, &Config{})
+			if err := c.Validate(); err != nil {
+				t.Errorf("expected requirement to be met, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"reports missing modules", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			logging := needle.NewModule("logging")
+			db := needle.NewModule("db").Requires(logging)
+
+			if err := c.Apply(db); err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+
+			err := c.Validate()
+			if err == nil || !strings.Contains(err.Error(), "module db requires module logging") {
+				t.Fatalf("expected unmet module requirement, got %v", err)
+			}
+
+			if err := c.Apply(logging); err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+			if err := c.Validate(); err != nil {
+				t.Errorf("expected requirement to be met, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"rejects invalid requirements", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			m := needle.NewModule("bad").Requires(42)
+
+			if err := c.Apply(m); err == nil {
+				t.Error("expected error for invalid requirement")
+			}
+		},
+	)
+}
+
+func TestModuleApplyRollback(t *testing.T) {
+	t.Parallel()
+
+	c := needle.New()
+	_ = needle.ProvideValue(c, &Database{Name: "existing"})
+
+	var decorated atomic.Int32
+	config := needle.NewModule("config")
+	needle.ModuleProvide(
+		config, func(ctx context.Context, r needle.Resolver) (*Config, error) {
+			return &Config{Port: 1}, nil
+		},
+	)
+	needle.ModuleDecorate(
+		config, func(ctx context.Context, r needle.Resolver, base *Config) (*Config, error) {
+			decorated.Add(1)
+			return base, nil
+		},
+	)
+
+	broken := needle.NewModule("broken")
+	needle.ModuleProvideValue(broken, &Logger{})
+	needle.ModuleProvideValue(broken, &Database{Name: "duplicate"})
+
+	if err := c.Apply(config, broken); err == nil {
+		t.Fatal("expected duplicate registration error")
+	}
+
+	if c.Size() != 1 {
+		t.Errorf("expected earlier registrations to be removed, got keys %v", c.Keys())
+	}
+	if needle.Has[*Config](c) || needle.Has[*Logger](c) {
+		t.Error("expected rolled back services to be absent")
+	}
+
+	if err := c.Apply(config); err != nil {
+		t.Fatalf("re-applying rolled back module failed: %v", err)
+	}
+	_ = needle.MustInvoke[*Config](c)
+	if decorated.Load() != 1 {
+		t.Errorf("expected decorator to run once, got %d", decorated.Load())
+	}
+}
+
+func TestModuleApplyRollbackRestoresDefaults(t *testing.T) {
+	t.Parallel()
+
+	c := needle.New()
+	_ = needle.ProvideDefaultValue(c, &Config{Host: "default"})
+	_ = needle.ProvideValue(c, &Database{Name: "existing"})
+
+	override := needle.NewModule("override")
+	needle.ModuleProvideValue(override, &Config{Host: "override"})
+	needle.ModuleProvideValue(override, &Database{Name: "duplicate"})
+
+	if err := c.Apply(override); err == nil {
+		t.Fatal("expected duplicate registration error")
+	}
+
+	if got := needle.MustInvoke[*Config](c).Host; got != "default" {
+		t.Errorf("expected default to be restored, got %s", got)
+	}
+
+	if err := needle.ProvideValue(c, &Config{Host: "explicit"}); err != nil {
+		t.Fatalf("expected restored default to remain overridable, got %v", err)
+	}
+	if got := needle.MustInvoke[*Config](c).Host; got != "explicit" {
+		t.Errorf("expected explicit registration, got %s", got)
+	}
+}
+
+func TestModuleApplyRollbackForgetsRunners(t *testing.T) {
+	t.Parallel()
+
+	c := needle.New()
+	_ = needle.ProvideValue(c, &Database{Name: "existing"})
+
+	var ran atomic.Bool
+	broken := needle.NewModule("broken")
+	needle.ModuleProvideValue(
+		broken, &Config{Host: "module"},
+		needle.WithRun(
+			func(ctx context.Context) error {
+				ran.Store(true)
+				return nil
+			},
+		),
+		needle.WithPhase("late"),
+		needle.WithStopTimeout(time.Second),
+	)
+	needle.ModuleProvideValue(broken, &Database{Name: "duplicate"})
+
+	if err := c.Apply(broken); err == nil {
+		t.Fatal("expected duplicate registration error")
+	}
+
+	if err := needle.ProvideValue(c, &Config{Host: "plain"}); err != nil {
+		t.Fatalf("ProvideValue failed: %v", err)
+	}
+	if err := c.Validate(); err != nil {
+		t.Errorf("expected the rolled-back phase to be gone, got %v", err)
+	}
+
+	ctx := context.Background()
+	if err := c.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	_ = c.Stop(ctx)
+	if ran.Load() {
+		t.Error("rolled-back runner should not run")
+	}
+}
@@ -0,0 +1,115 @@
+package needle
+
+import (
+	"context"
+	"fmt"
+	reflectPkg "reflect"
+
+	"github.com/danpasecinic/needle/internal/container"
+	"github.com/danpasecinic/needle/internal/reflect"
+)
+
+type ArgProvider[A, T any] func(ctx context.Context, r Resolver, arg A) (T, error)
+
+var assistedUnsupported = []string{
+	"WithOnStart", "WithOnStop", "WithScope", "WithPoolSize", "WithLazy", "WithDefault", "As",
+	"WithRun", "WithRestart", "WithPhase", "WithStartAfter", "WithStartTimeout", "WithStopTimeout",
+}
+
+func ProvideWithArgs[A, T any](c *Container, provider ArgProvider[A, T], opts ...ProviderOption) error {
+	cfg := &providerConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	if err := rejectOptions("ProvideWithArgs", cfg, assistedUnsupported...); err != nil {
+		return err
+	}
+	if cfg.argCacheSize > 0 && !reflectPkg.TypeFor[A]().Comparable() {
+		return fmt.Errorf("argument type %s is not comparable and cannot be cached", reflect.TypeName[A]())
+	}
+
+	key := c.localKey(argsKey[A, T](cfg.name))
+	if !c.admit(key, cfg.dependencies, cfg) {
+		return nil
+	}
+
+	resolver := c.resolver
+	wrappedProvider := func(ctx context.Context, r container.Resolver, arg any) (any, error) {
+		typed, ok := arg.(A)
+		if !ok && arg != nil {
+			var zero T
+			return zero, fmt.Errorf("argument type mismatch: expected %s, got %T", reflect.TypeName[A](), arg)
+		}
+		return provider(ctx, resolver, typed)
+	}
+	if cfg.retry != nil || cfg.timeout > 0 {
+		construct := wrappedProvider
+		wrappedProvider = func(ctx context.Context, r container.Resolver, arg any) (any, error) {
+			attempt := func(ctx context.Context, r container.Resolver) (any, error) {
+				return construct(ctx, r, arg)
+			}
+			return c.withRetry(key, attempt, cfg)(ctx, r)
+		}
+	}
+
+	if err := c.internal.RegisterWithArgs(key, wrappedProvider, cfg.dependencies); err != nil {
+		return err
+	}
+
+	if cfg.argCacheSize > 0 {
+		c.internal.SetArgCacheSize(key, cfg.argCacheSize)
+	}
+	applySelection(c, key, cfg)
+
+	return nil
+}
+
+func ProvideNamedWithArgs[A, T any](c *Container, name string, provider ArgProvider[A, T], opts ...ProviderOption) error {
+	opts = append(opts, WithName(name))
+	return ProvideWithArgs(c, provider, opts...)
+}
+
+func InvokeWith[A, T any](ctx context.Context, c *Container, arg A) (T, error) {
+	return invokeWith[A, T](ctx, c, argsKey[A, T](""), arg)
+}
+
+func InvokeNamedWith[A, T any](ctx context.Context, c *Container, name string, arg A) (T, error) {
+	return invokeWith[A, T](ctx, c, argsKey[A, T](name), arg)
+}
+
+func MustInvokeWith[A, T any](ctx context.Context, c *Container, arg A) T {
+	v, err := InvokeWith[A, T](ctx, c, arg)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func HasWithArgs[A, T any](c *Container) bool {
+	return c.internal.Has(argsKey[A, T](""))
+}
+
+func invokeWith[A, T any](ctx context.Context, c *Container, key string, arg A) (T, error) {
+	var zero T
+
+	instance, err := c.internal.ResolveWithArg(ctx, key, arg)
+	if err != nil {
+		return zero, errResolutionFailed(key, err)
+	}
+
+	typed, ok := instance.(T)
+	if !ok {
+		return zero, errResolutionFailed(key, errTypeMismatch[T](instance))
+	}
+
+	return typed, nil
+}
+
+func argsKey[A, T any](name string) string {
+	key := reflect.TypeKey[T]() + "(" + reflect.TypeKey[A]() + ")"
+	if name != "" {
+		key += "#" + name
+	}
+	return key
+}
@@ -0,0 +1,250 @@
+package needle_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/danpasecinic/needle"
+)
+
+type TenantClient struct {
+	TenantID string
+	Config   *Config
+}
+
+func TestProvideWithArgs(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"combines container dependencies and arguments", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.ProvideValue(c, &Config{Host: "api.local"})
+
+			err := needle.ProvideWithArgs(
+				c, func(ctx context.Context, r needle.Resolver, tenant string) (*TenantClient, error) {
+					return &TenantClient{TenantID: tenant, Config: needle.MustInvoke[*Config](c)}, nil
+				}, needle.WithDependencies("*github.com/danpasecinic/needle_test.Config"),
+			)
+			if err != nil {
+				t.Fatalf("ProvideWithArgs failed: %v", err)
+			}
+
+			client, err := needle.InvokeWith[string, *TenantClient](context.Background(), c, "acme")
+			if err != nil {
+				t.Fatalf("InvokeWith failed: %v", err)
+			}
+			if client.TenantID != "acme" || client.Config.Host != "api.local" {
+				t.Errorf("unexpected client: %+v", client)
+			}
+
+			other := needle.MustInvokeWith[string, *TenantClient](context.Background(), c, "acme")
+			if other == client {
+				t.Error("expected a new instance without argument cache")
+			}
+		},
+	)
+
+	t.Run(
+		"caches by argument with lru bound", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			var calls atomic.Int32
+
+			_ = needle.ProvideWithArgs(
+				c, func(ctx context.Context, r needle.Resolver, tenant string) (*TenantClient, error) {
+					calls.Add(1)
+					return &TenantClient{TenantID: tenant}, nil
+				}, needle.WithArgCache(2),
+			)
+
+			ctx := context.Background()
+			a1 := needle.MustInvokeWith[string, *TenantClient](ctx, c, "a")
+			a2 := needle.MustInvokeWith[string, *TenantClient](ctx, c, "a")
+			if a1 != a2 {
+				t.Error("expected cached instance for same argument")
+			}
+
+			_ = needle.MustInvokeWith[string, *TenantClient](ctx, c, "b")
+			_ = needle.MustInvokeWith[string, *TenantClient](ctx, c, "c")
+			a3 := needle.MustInvokeWith[string, *TenantClient](ctx, c, "a")
+			if a3 == a1 {
+				t.Error("expected least recently used entry to be evicted")
+			}
+			if calls.Load() != 4 {
+				t.Errorf("expected 4 provider calls, got %d", calls.Load())
+			}
+		},
+	)
+
+	t.Run(
+		"rejects cache for non-comparable arguments", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			err := needle.ProvideWithArgs(
+				c, func(ctx context.Context, r needle.Resolver, ids []string) (*TenantClient, error) {
+					return &TenantClient{}, nil
+				}, needle.WithArgCache(10),
+			)
+			if err == nil {
+				t.Error("expected error for non-comparable argument cache")
+			}
+		},
+	)
+
+	t.Run(
+		"bypasses the cache for unhashable arguments", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			builds := 0
+			_ = needle.ProvideWithArgs(
+				c, func(ctx context.Context, r needle.Resolver, arg any) (*TenantClient, error) {
+					builds++
+					return &TenantClient{TenantID: fmt.Sprint(arg)}, nil
+				}, needle.WithArgCache(4),
+			)
+
+			ctx := context.Background()
+			for range 2 {
+				if _, err := needle.InvokeWith[any, *TenantClient](ctx, c, []int{1}); err != nil {
+					t.Fatalf("InvokeWith failed: %v", err)
+				}
+			}
+			if builds != 2 {
+				t.Errorf("expected unhashable arguments to skip the cache, got %d builds", builds)
+			}
+
+			first := needle.MustInvokeWith[any, *TenantClient](ctx, c, "acme")
+			if needle.MustInvokeWith[any, *TenantClient](ctx, c, "acme") != first {
+				t.Error("expected hashable arguments to be cached")
+			}
+		},
+	)
+
+	t.Run(
+		"plain invoke requires an argument", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.ProvideWithArgs(
+				c, func(ctx context.Context, r needle.Resolver, tenant string) (*TenantClient, error) {
+					return &TenantClient{TenantID: tenant}, nil
+				},
+			)
+
+			if _, err := needle.Invoke[*TenantClient](c); err == nil {
+				t.Error("expected plain Invoke to fail")
+			}
+			if !needle.HasWithArgs[string, *TenantClient](c) {
+				t.Error("expected HasWithArgs to report registration")
+			}
+		},
+	)
+
+	t.Run(
+		"named providers", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.ProvideNamedWithArgs(
+				c, "eu", func(ctx context.Context, r needle.Resolver, tenant string) (*TenantClient, error) {
+					return &TenantClient{TenantID: "eu-" + tenant}, nil
+				},
+			)
+
+			client, err := needle.InvokeNamedWith[string, *TenantClient](context.Background(), c, "eu", "acme")
+			if err != nil {
+				t.Fatalf("InvokeNamedWith failed: %v", err)
+			}
+			if client.TenantID != "eu-acme" {
+				t.Errorf("expected eu-acme, got %s", client.TenantID)
+			}
+		},
+	)
+
+	t.Run(
+		"retries per argument", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			var calls atomic.Int32
+
+			err := needle.ProvideWithArgs(
+				c, func(ctx context.Context, r needle.Resolver, tenant string) (*TenantClient, error) {
+					if calls.Add(1) < 2 {
+						return nil, errors.New("transient")
+					}
+					return &TenantClient{TenantID: tenant}, nil
+				}, needle.WithRetry(needle.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}),
+			)
+			if err != nil {
+				t.Fatalf("ProvideWithArgs failed: %v", err)
+			}
+
+			client, err := needle.InvokeWith[string, *TenantClient](context.Background(), c, "acme")
+			if err != nil {
+				t.Fatalf("InvokeWith failed: %v", err)
+			}
+			if client.TenantID != "acme" || calls.Load() != 2 {
+				t.Errorf("expected success on second attempt, got %+v after %d calls", client, calls.Load())
+			}
+		},
+	)
+
+	t.Run(
+		"rejects lifecycle options", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			err := needle.ProvideWithArgs(
+				c, func(ctx context.Context, r needle.Resolver, tenant string) (*TenantClient, error) {
+					return &TenantClient{TenantID: tenant}, nil
+				}, needle.WithOnStart(func(ctx context.Context) error { return nil }),
+			)
+			if err == nil || !strings.Contains(err.Error(), "WithOnStart") {
+				t.Fatalf("expected unsupported option error, got %v", err)
+			}
+			if needle.HasWithArgs[string, *TenantClient](c) {
+				t.Error("rejected provider should not be registered")
+			}
+		},
+	)
+}
+
+func TestProvideWithArgsGraphAndValidate(t *testing.T) {
+	t.Parallel()
+
+	c := needle.New()
+	_ = needle.ProvideWithArgs(
+		c, func(ctx context.Context, r needle.Resolver, tenant string) (*TenantClient, error) {
+			return &TenantClient{TenantID: tenant}, nil
+		}, needle.WithDependencies("*github.com/danpasecinic/needle_test.Config"),
+	)
+
+	if err := c.Validate(); err == nil {
+		t.Error("expected validation error for missing dependency")
+	}
+
+	_ = needle.ProvideValue(c, &Config{})
+	if err := c.Validate(); err != nil {
+		t.Errorf("unexpected validation error: %v", err)
+	}
+
+	if !strings.Contains(c.SprintGraph(), "needle_test.TenantClient(string)") {
+		t.Errorf("expected assisted provider in graph, got: %s", c.SprintGraph())
+	}
+
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatalf("Start should skip assisted providers: %v", err)
+	}
+	_ = c.Stop(context.Background())
+}
@@ -35,12 +35,7 @@ func InvokeStructCtx[T any](ctx context.Context, c *Container) (T, error) {
 	structVal := reflectPkg.New(t).Elem()
 
 	for _, field := range fields {
-		var key string
-		if field.Named != "" {
-			key = field.TypeKey + "#" + field.Named
-		} else {
-			key = field.TypeKey
-		}
+		key := c.scopeKey(fieldKey(field))
 
 		if !c.internal.Has(key) {
 			if field.Optional {
@@ -49,6 +44,16 @@ func InvokeStructCtx[T any](ctx context.Context, c *Container) (T, error) {
 			return zero, errServiceNotFound(key)
 		}
 
+		fieldVal := structVal.Field(field.Index)
+		if !fieldVal.CanSet() {
+			return zero, fmt.Errorf("cannot set field %s (unexported)", field.Name)
+		}
+
+		if isDeferred(field.Type) {
+			fieldVal.Set(newDeferred(c, field.Type, key))
+			continue
+		}
+
 		instance, err := c.internal.Resolve(ctx, key)
 		if err != nil {
 			if field.Optional {
@@ -57,11 +62,6 @@ func InvokeStructCtx[T any](ctx context.Context, c *Container) (T, error) {
 			return zero, errResolutionFailed(field.Name, err)
 		}
 
-		fieldVal := structVal.Field(field.Index)
-		if !fieldVal.CanSet() {
-			return zero, fmt.Errorf("cannot set field %s (unexported)", field.Name)
-		}
-
 		instanceVal := reflectPkg.ValueOf(instance)
 		if !instanceVal.Type().AssignableTo(fieldVal.Type()) {
 			return zero, fmt.Errorf(
@@ -102,21 +102,14 @@ func ProvideFunc[T any](c *Container, constructor any, opts ...ProviderOption) e
 
 	hasError := fnType.NumOut() == 2 && fnType.Out(1).Implements(reflectPkg.TypeOf((*error)(nil)).Elem())
 
-	deps := make([]string, len(params))
-	for i, p := range params {
-		deps[i] = p.TypeKey
-	}
+	deps, deferred := funcDependencies(params)
 
 	provider := func(ctx context.Context, r Resolver) (T, error) {
 		var zero T
 
-		args := make([]reflectPkg.Value, len(params))
-		for i, p := range params {
-			instance, err := c.internal.Resolve(ctx, p.TypeKey)
-			if err != nil {
-				return zero, fmt.Errorf("failed to resolve parameter %d (%s): %w", i, p.TypeKey, err)
-			}
-			args[i] = reflectPkg.ValueOf(instance)
+		args, err := resolveFuncArgs(ctx, c, params)
+		if err != nil {
+			return zero, err
 		}
 
 		results := fnVal.Call(args)
@@ -128,7 +121,7 @@ func ProvideFunc[T any](c *Container, constructor any, opts ...ProviderOption) e
 		return results[0].Interface().(T), nil
 	}
 
-	opts = append([]ProviderOption{WithDependencies(deps...)}, opts...)
+	opts = append([]ProviderOption{WithDependencies(deps...), withDeferredDependencies(deferred...)}, opts...)
 	return Provide(c, provider, opts...)
 }
 
@@ -143,19 +136,9 @@ func ProvideStruct[T any](c *Container, opts ...ProviderOption) error {
 		return InvokeStructCtx[T](ctx, c)
 	}
 
-	fields, _ := reflect.StructFields[T](TagKey)
-	deps := make([]string, 0, len(fields))
-	for _, f := range fields {
-		if !f.Optional {
-			if f.Named != "" {
-				deps = append(deps, f.TypeKey+"#"+f.Named)
-			} else {
-				deps = append(deps, f.TypeKey)
-			}
-		}
-	}
+	deps, deferred := structDependencies[T]()
 
-	opts = append([]ProviderOption{WithDependencies(deps...)}, opts...)
+	opts = append([]ProviderOption{WithDependencies(deps...), withDeferredDependencies(deferred...)}, opts...)
 	return Provide(c, provider, opts...)
 }
 
@@ -164,3 +147,58 @@ func MustProvideStruct[T any](c *Container, opts ...ProviderOption) {
 		panic(err)
 	}
 }
+
+func fieldKey(field reflect.FieldInfo) string {
+	if isDeferred(field.Type) {
+		return deferredTargetKey(field.Type, field.Named)
+	}
+	if field.Named != "" {
+		return field.TypeKey + "#" + field.Named
+	}
+	return field.TypeKey
+}
+
+func structDependencies[T any]() (deps []string, deferred []string) {
+	fields, _ := reflect.StructFields[T](TagKey)
+	deps = make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f.Optional {
+			continue
+		}
+		if isDeferred(f.Type) {
+			deferred = append(deferred, fieldKey(f))
+		} else {
+			deps = append(deps, fieldKey(f))
+		}
+	}
+	return deps, deferred
+}
+
+func funcDependencies(params []reflect.FuncParamInfo) (deps []string, deferred []string) {
+	deps = make([]string, 0, len(params))
+	for _, p := range params {
+		if isDeferred(p.Type) {
+			deferred = append(deferred, deferredTargetKey(p.Type, ""))
+		} else {
+			deps = append(deps, p.TypeKey)
+		}
+	}
+	return deps, deferred
+}
+
+func resolveFuncArgs(ctx context.Context, c *Container, params []reflect.FuncParamInfo) ([]reflectPkg.Value, error) {
+	args := make([]reflectPkg.Value, len(params))
+	for i, p := range params {
+		if isDeferred(p.Type) {
+			args[i] = newDeferred(c, p.Type, c.scopeKey(deferredTargetKey(p.Type, "")))
+			continue
+		}
+
+		instance, err := c.internal.Resolve(ctx, c.scopeKey(p.TypeKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve parameter %d (%s): %w", i, p.TypeKey, err)
+		}
+		args[i] = reflectPkg.ValueOf(instance)
+	}
+	return args, nil
+}
@@ -190,6 +190,7 @@ func groupByCategory(results []BenchmarkResult) []CategoryResults {
 		"Named_10",
 		"Lifecycle_10", "Lifecycle_50",
 		"LifecycleWithWork_10", "LifecycleWithWork_50",
+		"LifecycleUneven_8",
 	}
 
 	for _, catKey := range categoryOrder {
@@ -302,6 +303,7 @@ func formatCategoryTitle(cat string) string {
 		"Lifecycle_50":         "Lifecycle Start/Stop (50 services)",
 		"LifecycleWithWork_10": "Lifecycle with Work (10 services, 1ms each)",
 		"LifecycleWithWork_50": "Lifecycle with Work (50 services, 1ms each)",
+		"LifecycleUneven_8":    "Lifecycle with Uneven Work (8 chains of 4 services)",
 	}
 
 	if title, ok := titles[cat]; ok {
@@ -59,6 +59,82 @@ func BenchmarkLifecycleWithWork_50_Fx(b *testing.B) {
 	benchmarkLifecycleFxWithWork(b, 50, time.Millisecond)
 }
 
+func BenchmarkLifecycleUneven_8_Needle(b *testing.B) {
+	benchmarkLifecycleNeedleUneven(b, 8, false)
+}
+
+func BenchmarkLifecycleUneven_8_NeedleParallel(b *testing.B) {
+	benchmarkLifecycleNeedleUneven(b, 8, true)
+}
+
+func BenchmarkLifecycleUneven_8_Fx(b *testing.B) {
+	benchmarkLifecycleFxUneven(b, 8)
+}
+
+// Uneven graphs are built from chains of equal depth where each chain has
+// its slow service at a different depth, so every level contains one slow
+// service but no single chain is slow everywhere.
+const unevenDepth = 4
+
+func unevenName(chain, depth int) string {
+	return fmt.Sprintf("svc_%d_%d", chain, depth)
+}
+
+func unevenWork(chain, depth int) time.Duration {
+	if chain%unevenDepth == depth {
+		return 4 * time.Millisecond
+	}
+	return time.Millisecond
+}
+
+func benchmarkLifecycleNeedleUneven(b *testing.B, chains int, parallel bool) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		var opts []needle.Option
+		if parallel {
+			opts = append(opts, needle.WithParallel())
+		}
+		c := needle.New(opts...)
+
+		for chain := 0; chain < chains; chain++ {
+			for depth := 0; depth < unevenDepth; depth++ {
+				idx := chain*unevenDepth + depth
+				work := unevenWork(chain, depth)
+				providerOpts := []needle.ProviderOption{
+					needle.WithOnStart(
+						func(ctx context.Context) error {
+							time.Sleep(work)
+							return nil
+						},
+					),
+					needle.WithOnStop(
+						func(ctx context.Context) error {
+							time.Sleep(work)
+							return nil
+						},
+					),
+				}
+				if depth > 0 {
+					dep := fmt.Sprintf("%T#%s", &Config{}, unevenName(chain, depth-1))
+					providerOpts = append(providerOpts, needle.WithDependencies(dep))
+				}
+				_ = needle.ProvideNamed(
+					c, unevenName(chain, depth), func(ctx context.Context, r needle.Resolver) (*Config, error) {
+						return &Config{Port: idx}, nil
+					},
+					providerOpts...,
+				)
+			}
+		}
+
+		ctx := context.Background()
+		b.StartTimer()
+		_ = c.Start(ctx)
+		_ = c.Stop(ctx)
+	}
+}
+
 func benchmarkLifecycleNeedle(b *testing.B, count int, parallel bool) {
 	b.ReportAllocs()
 	for i := 0; i < b.N; i++ {
@@ -211,3 +287,59 @@ func benchmarkLifecycleFxWithWork(b *testing.B, count int, work time.Duration) {
 		_ = app.Stop(ctx)
 	}
 }
+
+func benchmarkLifecycleFxUneven(b *testing.B, chains int) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		var providers []fx.Option
+		invokers := make([]any, 0, chains)
+		for chain := 0; chain < chains; chain++ {
+			for depth := 0; depth < unevenDepth; depth++ {
+				idx := chain*unevenDepth + depth
+				work := unevenWork(chain, depth)
+				constructor := func(lc fx.Lifecycle) *Config {
+					lc.Append(
+						fx.Hook{
+							OnStart: func(ctx context.Context) error {
+								time.Sleep(work)
+								return nil
+							},
+							OnStop: func(ctx context.Context) error {
+								time.Sleep(work)
+								return nil
+							},
+						},
+					)
+					return &Config{Port: idx}
+				}
+
+				annotations := []fx.Annotation{fx.ResultTags(fmt.Sprintf(`name:"%s"`, unevenName(chain, depth)))}
+				var provider any = constructor
+				if depth > 0 {
+					provider = func(lc fx.Lifecycle, _ *Config) *Config { return constructor(lc) }
+					annotations = append(
+						annotations, fx.ParamTags(``, fmt.Sprintf(`name:"%s"`, unevenName(chain, depth-1))),
+					)
+				}
+				providers = append(providers, fx.Provide(fx.Annotate(provider, annotations...)))
+			}
+
+			invokers = append(
+				invokers, fx.Annotate(
+					func(*Config) {},
+					fx.ParamTags(fmt.Sprintf(`name:"%s"`, unevenName(chain, unevenDepth-1))),
+				),
+			)
+		}
+
+		opts := []fx.Option{fx.NopLogger, fx.Invoke(invokers...)}
+		opts = append(opts, providers...)
+		app := fx.New(opts...)
+
+		ctx := context.Background()
+		b.StartTimer()
+		_ = app.Start(ctx)
+		_ = app.Stop(ctx)
+	}
+}
@@ -2,6 +2,9 @@ package needle
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	reflectPkg "reflect"
 
 	"github.com/danpasecinic/needle/internal/container"
 	"github.com/danpasecinic/needle/internal/reflect"
@@ -21,21 +24,34 @@ func Bind[I, T any](c *Container, opts ...ProviderOption) error {
 	if cfg.name != "" {
 		interfaceKey = reflect.TypeKeyNamed[I](cfg.name)
 	}
+	interfaceKey = c.localKey(interfaceKey)
 
+	if !c.admit(interfaceKey, []string{implKey}, cfg) {
+		return nil
+	}
+
+	return skipDefault(registerBinding(c, interfaceKey, implKey, cfg))
+}
+
+func registerBinding(c *Container, interfaceKey, implKey string, cfg *providerConfig) error {
 	wrappedProvider := func(ctx context.Context, r container.Resolver) (any, error) {
-		return r.Resolve(ctx, implKey)
+		return r.Resolve(ctx, c.scopeKey(implKey))
 	}
 
-	if err := c.internal.Register(interfaceKey, wrappedProvider, []string{implKey}); err != nil {
+	bindingCfg := &providerConfig{dependencies: []string{implKey}, isDefault: cfg.isDefault}
+	if err := registerProvider(c, interfaceKey, wrappedProvider, bindingCfg); err != nil {
 		return err
 	}
 
+	c.internal.SetBinding(interfaceKey, implKey)
+
 	for _, hook := range cfg.onStart {
 		c.internal.AddOnStart(interfaceKey, hook)
 	}
 	for _, hook := range cfg.onStop {
 		c.internal.AddOnStop(interfaceKey, hook)
 	}
+	applySelection(c, interfaceKey, cfg)
 
 	return nil
 }
@@ -45,6 +61,70 @@ func BindNamed[I, T any](c *Container, name string, opts ...ProviderOption) erro
 	return Bind[I, T](c, opts...)
 }
 
+func As[I any]() ProviderOption {
+	return func(cfg *providerConfig) {
+		cfg.aliases = append(cfg.aliases, reflectPkg.TypeFor[I]())
+	}
+}
+
+func AutoBind[T any](c *Container, ifaces ...reflectPkg.Type) error {
+	return applyAliases(c, reflect.TypeKey[T](), reflectPkg.TypeFor[T](), &providerConfig{aliases: ifaces})
+}
+
+func AutoBindNamed[T any](c *Container, name string, ifaces ...reflectPkg.Type) error {
+	return applyAliases(c, reflect.TypeKeyNamed[T](name), reflectPkg.TypeFor[T](), &providerConfig{name: name, aliases: ifaces})
+}
+
+func applyAliases(c *Container, implKey string, implType reflectPkg.Type, cfg *providerConfig) error {
+	name := cfg.name
+	ifaces := cfg.aliases
+	registered := make([]string, 0, len(ifaces))
+
+	for _, iface := range ifaces {
+		if err := checkAlias(implType, iface); err != nil {
+			unregisterAll(c, registered)
+			return err
+		}
+
+		interfaceKey := reflect.TypeKeyOf(iface)
+		if name != "" {
+			interfaceKey += "#" + name
+		}
+		interfaceKey = c.localKey(interfaceKey)
+		if bound, ok := c.internal.Binding(interfaceKey); ok && bound == implKey {
+			continue
+		}
+
+		err := registerBinding(c, interfaceKey, implKey, &providerConfig{isDefault: cfg.isDefault})
+		if errors.Is(err, container.ErrDefaultSkipped) {
+			continue
+		}
+		if err != nil {
+			unregisterAll(c, registered)
+			return err
+		}
+		registered = append(registered, interfaceKey)
+	}
+
+	return nil
+}
+
+func checkAlias(implType, iface reflectPkg.Type) error {
+	if iface == nil || iface.Kind() != reflectPkg.Interface {
+		return fmt.Errorf("cannot bind %s as %v: not an interface", implType, iface)
+	}
+	if implType == nil || !implType.Implements(iface) {
+		return fmt.Errorf("cannot bind %s as %s: does not implement interface", implType, iface)
+	}
+	return nil
+}
+
+func unregisterAll(c *Container, keys []string) {
+	for _, key := range keys {
+		c.internal.Unregister(key)
+	}
+}
+
 func Decorate[T any](c *Container, decorator Decorator[T]) {
 	key := reflect.TypeKey[T]()
 
@@ -0,0 +1,171 @@
+package needle_test
+
+import (
+	"context"
+	"io"
+	reflectPkg "reflect"
+	"strings"
+	"testing"
+
+	"github.com/danpasecinic/needle"
+)
+
+type Store interface {
+	Get(key string) string
+}
+
+type MemoryStore struct {
+	Name string
+}
+
+func (s *MemoryStore) Get(key string) string { return s.Name + ":" + key }
+func (s *MemoryStore) Close() error          { return nil }
+
+func TestProvideAs(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"exposes provider as several interfaces", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			err := needle.Provide(
+				c, func(ctx context.Context, r needle.Resolver) (*MemoryStore, error) {
+					return &MemoryStore{Name: "mem"}, nil
+				}, needle.As[Store](), needle.As[io.Closer](),
+			)
+			if err != nil {
+				t.Fatalf("Provide failed: %v", err)
+			}
+
+			store := needle.MustInvoke[Store](c)
+			closer := needle.MustInvoke[io.Closer](c)
+			impl := needle.MustInvoke[*MemoryStore](c)
+
+			if store.(*MemoryStore) != impl || closer.(*MemoryStore) != impl {
+				t.Error("expected bindings to resolve to the same instance")
+			}
+		},
+	)
+
+	t.Run(
+		"named bindings use named keys", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.ProvideNamedValue(c, "cache", &MemoryStore{Name: "cache"}, needle.As[Store]())
+
+			store, err := needle.InvokeNamed[Store](c, "cache")
+			if err != nil {
+				t.Fatalf("InvokeNamed failed: %v", err)
+			}
+			if store.Get("k") != "cache:k" {
+				t.Errorf("unexpected store: %s", store.Get("k"))
+			}
+			if needle.Has[Store](c) {
+				t.Error("named binding should not register the unnamed key")
+			}
+		},
+	)
+
+	t.Run(
+		"rejects interfaces that are not implemented", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			err := needle.Provide(
+				c, func(ctx context.Context, r needle.Resolver) (*Config, error) {
+					return &Config{}, nil
+				}, needle.As[Store](),
+			)
+			if err == nil {
+				t.Fatal("expected error for unimplemented interface")
+			}
+			if needle.Has[*Config](c) {
+				t.Error("failed registration should be rolled back")
+			}
+		},
+	)
+
+	t.Run(
+		"rolls back earlier bindings on conflict", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.ProvideValue[io.Closer](c, &MemoryStore{})
+
+			err := needle.ProvideValue(c, &MemoryStore{}, needle.As[Store](), needle.As[io.Closer]())
+			if err == nil {
+				t.Fatal("expected duplicate binding error")
+			}
+			if needle.Has[Store](c) || needle.Has[*MemoryStore](c) {
+				t.Error("expected registration to be rolled back")
+			}
+		},
+	)
+}
+
+func TestAutoBind(t *testing.T) {
+	t.Parallel()
+
+	c := needle.New()
+	_ = needle.ProvideValue(c, &MemoryStore{Name: "auto"})
+
+	err := needle.AutoBind[*MemoryStore](c, reflectPkg.TypeFor[Store](), reflectPkg.TypeFor[io.Closer]())
+	if err != nil {
+		t.Fatalf("AutoBind failed: %v", err)
+	}
+
+	if needle.MustInvoke[Store](c).Get("x") != "auto:x" {
+		t.Error("expected Store binding")
+	}
+	if !needle.Has[io.Closer](c) {
+		t.Error("expected io.Closer binding")
+	}
+
+	if err := needle.AutoBind[*MemoryStore](c, reflectPkg.TypeFor[*Config]()); err == nil {
+		t.Error("expected error for non-interface type")
+	}
+}
+
+func TestModuleBindNamed(t *testing.T) {
+	t.Parallel()
+
+	c := needle.New()
+	module := needle.NewModule("stores")
+	needle.ModuleProvideValue(module, &MemoryStore{Name: "mod"})
+	needle.ModuleBind[Store, *MemoryStore](module, needle.WithName("primary"))
+
+	if err := c.Apply(module); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	store, err := needle.InvokeNamed[Store](c, "primary")
+	if err != nil {
+		t.Fatalf("InvokeNamed failed: %v", err)
+	}
+	if store.Get("k") != "mod:k" {
+		t.Errorf("unexpected store: %s", store.Get("k"))
+	}
+}
+
+func TestBindingGraphEdges(t *testing.T) {
+	t.Parallel()
+
+	c := needle.New()
+	_ = needle.ProvideValue(c, &MemoryStore{}, needle.As[Store]())
+
+	var found bool
+	for _, svc := range c.Graph().Services {
+		if strings.HasSuffix(svc.Key, "Store") && svc.Binding != "" {
+			found = len(svc.Dependencies) == 1 && svc.Dependencies[0] == svc.Binding
+		}
+	}
+	if !found {
+		t.Error("expected binding edge in graph")
+	}
+
+	if !strings.Contains(c.SprintGraphDOT(), "arrowhead=empty") {
+		t.Error("expected binding edge style in DOT output")
+	}
+}
@@ -0,0 +1,441 @@
+package needle
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	reflectPkg "reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/danpasecinic/needle/internal/reflect"
+)
+
+type ConfigSource func(target any) error
+
+type configState struct {
+	mu       sync.RWMutex
+	reloadMu sync.Mutex
+	entries  map[string]*configEntry
+}
+
+type configEntry struct {
+	value any
+	err   error
+	load  func() (any, error)
+}
+
+type configField struct {
+	index     []int
+	path      string
+	env       string
+	flag      string
+	kind      reflectPkg.Kind
+	def       string
+	hasDef    bool
+	required  bool
+	sensitive bool
+}
+
+const redacted = "[redacted]"
+
+var durationType = reflectPkg.TypeFor[time.Duration]()
+
+func newConfigState() *configState {
+	return &configState{entries: make(map[string]*configEntry)}
+}
+
+func ProvideConfig[T any](c *Container, sources ...ConfigSource) error {
+	value, err := loadConfig[T](sources)
+	if err != nil {
+		return errConfigFailed(reflect.TypeName[T](), err)
+	}
+
+	if err := ProvideValue(c, value); err != nil {
+		return err
+	}
+
+	key := c.scopeKey(reflect.TypeKey[T]())
+
+	c.configs.mu.Lock()
+	c.configs.entries[key] = &configEntry{
+		value: value,
+		err:   validateConfig(value),
+		load: func() (any, error) {
+			return loadConfig[T](sources)
+		},
+	}
+	c.configs.mu.Unlock()
+
+	return nil
+}
+
+func MustProvideConfig[T any](c *Container, sources ...ConfigSource) {
+	if err := ProvideConfig[T](c, sources...); err != nil {
+		panic(err)
+	}
+}
+
+func FromJSON(path string) ConfigSource {
+	return func(target any) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, target); err != nil {
+			return fmt.Errorf("parse %s: %w", path, err)
+		}
+		return nil
+	}
+}
+
+func FromEnv(prefix string) ConfigSource {
+	if prefix != "" && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+
+	return func(target any) error {
+		v := reflectPkg.ValueOf(target).Elem()
+		for _, f := range configFields(v.Type()) {
+			raw, ok := os.LookupEnv(prefix + f.env)
+			if !ok {
+				continue
+			}
+			if err := setConfigValue(v.FieldByIndex(f.index), raw); err != nil {
+				return fmt.Errorf("env %s%s: %w", prefix, f.env, err)
+			}
+		}
+		return nil
+	}
+}
+
+func FromFlags(fs *flag.FlagSet, args []string) ConfigSource {
+	if fs == nil {
+		fs = flag.NewFlagSet("config", flag.ContinueOnError)
+	}
+
+	return func(target any) error {
+		v := reflectPkg.ValueOf(target).Elem()
+		fields := configFields(v.Type())
+
+		defineConfigFlags(fs, fields)
+		if !fs.Parsed() {
+			if err := fs.Parse(args); err != nil {
+				return err
+			}
+		}
+
+		values := make(map[string]string)
+		fs.Visit(
+			func(f *flag.Flag) {
+				values[f.Name] = f.Value.String()
+			},
+		)
+
+		for _, f := range fields {
+			raw, ok := values[f.flag]
+			if !ok {
+				continue
+			}
+			if err := setConfigValue(v.FieldByIndex(f.index), raw); err != nil {
+				return fmt.Errorf("flag -%s: %w", f.flag, err)
+			}
+		}
+		return nil
+	}
+}
+
+func DefineConfigFlags[T any](fs *flag.FlagSet) {
+	t := reflectPkg.TypeFor[T]()
+	if t.Kind() == reflectPkg.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflectPkg.Struct {
+		defineConfigFlags(fs, configFields(t))
+	}
+}
+
+type configFlag struct {
+	raw    string
+	isBool bool
+}
+
+func (f *configFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.raw
+}
+
+func (f *configFlag) Set(raw string) error {
+	f.raw = raw
+	return nil
+}
+
+func (f *configFlag) IsBoolFlag() bool {
+	return f.isBool
+}
+
+func defineConfigFlags(fs *flag.FlagSet, fields []configField) {
+	for _, f := range fields {
+		if fs.Lookup(f.flag) != nil {
+			continue
+		}
+		fs.Var(&configFlag{isBool: f.kind == reflectPkg.Bool}, f.flag, f.path)
+	}
+}
+
+func (c *Container) validateConfigs() error {
+	c.configs.mu.RLock()
+	defer c.configs.mu.RUnlock()
+
+	keys := make([]string, 0, len(c.configs.entries))
+	for key, entry := range c.configs.entries {
+		if entry.err != nil {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	sort.Strings(keys)
+	errs := make([]error, len(keys))
+	for i, key := range keys {
+		errs[i] = fmt.Errorf("invalid config %s: %w", key, c.configs.entries[key].err)
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Container) describeConfig(key string) map[string]string {
+	c.configs.mu.RLock()
+	entry, ok := c.configs.entries[key]
+	var value any
+	if ok {
+		value = entry.value
+	}
+	c.configs.mu.RUnlock()
+
+	if !ok {
+		return nil
+	}
+	return describeConfig(value)
+}
+
+func loadConfig[T any](sources []ConfigSource) (T, error) {
+	var zero T
+
+	t := reflectPkg.TypeFor[T]()
+	isPtr := t.Kind() == reflectPkg.Ptr
+	if isPtr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflectPkg.Struct {
+		return zero, fmt.Errorf("config must be a struct type, got %s", t.Kind())
+	}
+
+	ptr := reflectPkg.New(t)
+	for _, f := range configFields(t) {
+		if !f.hasDef {
+			continue
+		}
+		if err := setConfigValue(ptr.Elem().FieldByIndex(f.index), f.def); err != nil {
+			return zero, fmt.Errorf("default for %s: %w", f.path, err)
+		}
+	}
+
+	for _, source := range sources {
+		if err := source(ptr.Interface()); err != nil {
+			return zero, err
+		}
+	}
+
+	if isPtr {
+		return ptr.Interface().(T), nil
+	}
+	return ptr.Elem().Interface().(T), nil
+}
+
+func validateConfig(value any) error {
+	v := reflectPkg.ValueOf(value)
+	if v.Kind() == reflectPkg.Ptr {
+		v = v.Elem()
+	}
+
+	var errs []error
+	for _, f := range configFields(v.Type()) {
+		if f.required && v.FieldByIndex(f.index).IsZero() {
+			errs = append(errs, fmt.Errorf("%s is required", f.path))
+		}
+	}
+
+	if validator, ok := value.(interface{ Validate() error }); ok {
+		if err := validator.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func describeConfig(value any) map[string]string {
+	v := reflectPkg.ValueOf(value)
+	if v.Kind() == reflectPkg.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	fields := configFields(v.Type())
+	description := make(map[string]string, len(fields))
+	for _, f := range fields {
+		if f.sensitive {
+			description[f.path] = redacted
+			continue
+		}
+		description[f.path] = fmt.Sprint(v.FieldByIndex(f.index).Interface())
+	}
+	return description
+}
+
+func configFields(t reflectPkg.Type) []configField {
+	return appendConfigFields(nil, t, nil, nil)
+}
+
+func appendConfigFields(fields []configField, t reflectPkg.Type, index []int, path []string) []configField {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name := sf.Name
+		if tag, _, _ := strings.Cut(sf.Tag.Get("json"), ","); tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+
+		fieldIndex := append(append([]int{}, index...), i)
+		fieldPath := append(append([]string{}, path...), name)
+
+		if sf.Type.Kind() == reflectPkg.Struct {
+			fields = appendConfigFields(fields, sf.Type, fieldIndex, fieldPath)
+			continue
+		}
+
+		f := configField{
+			index:     fieldIndex,
+			path:      strings.Join(fieldPath, "."),
+			kind:      sf.Type.Kind(),
+			env:       sf.Tag.Get("env"),
+			flag:      sf.Tag.Get("flag"),
+			required:  sf.Tag.Get("required") == "true",
+			sensitive: sf.Tag.Get("sensitive") == "true",
+		}
+		f.def, f.hasDef = sf.Tag.Lookup("default")
+
+		if f.env == "" {
+			f.env = joinWords(fieldPath, "_", unicode.ToUpper)
+		}
+		if f.flag == "" {
+			f.flag = joinWords(fieldPath, "-", unicode.ToLower)
+		}
+
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+func joinWords(path []string, sep string, mapCase func(rune) rune) string {
+	var words []string
+	for _, segment := range path {
+		words = append(words, splitWords(segment)...)
+	}
+	for i, word := range words {
+		words[i] = strings.Map(mapCase, word)
+	}
+	return strings.Join(words, sep)
+}
+
+func splitWords(s string) []string {
+	var words []string
+	runes := []rune(s)
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		if runes[i] == '_' || runes[i] == '-' {
+			words = append(words, string(runes[start:i]))
+			start = i + 1
+			continue
+		}
+		upper := unicode.IsUpper(runes[i])
+		boundary := upper && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1])))
+		if boundary && i > start {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+func setConfigValue(v reflectPkg.Value, raw string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflectPkg.String:
+		v.SetString(raw)
+	case reflectPkg.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflectPkg.Int, reflectPkg.Int8, reflectPkg.Int16, reflectPkg.Int32, reflectPkg.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflectPkg.Uint, reflectPkg.Uint8, reflectPkg.Uint16, reflectPkg.Uint32, reflectPkg.Uint64:
+		n, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflectPkg.Float32, reflectPkg.Float64:
+		n, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case reflectPkg.Slice:
+		if v.Type().Elem().Kind() != reflectPkg.String {
+			return fmt.Errorf("unsupported slice type %s", v.Type())
+		}
+		parts := strings.Split(raw, ",")
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+		v.Set(reflectPkg.ValueOf(parts).Convert(v.Type()))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
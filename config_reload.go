@@ -0,0 +1,171 @@
+package needle
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	reflectPkg "reflect"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+type WatchOption func(*watchConfig)
+
+type watchConfig struct {
+	files    []string
+	interval time.Duration
+	signals  []os.Signal
+}
+
+func WatchFiles(paths ...string) WatchOption {
+	return func(cfg *watchConfig) {
+		cfg.files = append(cfg.files, paths...)
+	}
+}
+
+func WatchInterval(interval time.Duration) WatchOption {
+	return func(cfg *watchConfig) {
+		cfg.interval = interval
+	}
+}
+
+func WatchSignals(signals ...os.Signal) WatchOption {
+	return func(cfg *watchConfig) {
+		cfg.signals = signals
+	}
+}
+
+func (c *Container) ReloadConfig(ctx context.Context) error {
+	c.configs.reloadMu.Lock()
+	defer c.configs.reloadMu.Unlock()
+
+	start := time.Now()
+
+	c.configs.mu.RLock()
+	keys := make([]string, 0, len(c.configs.entries))
+	for key := range c.configs.entries {
+		keys = append(keys, key)
+	}
+	c.configs.mu.RUnlock()
+	sort.Strings(keys)
+
+	values := make(map[string]any)
+	for _, key := range keys {
+		c.configs.mu.RLock()
+		entry := c.configs.entries[key]
+		current := entry.value
+		c.configs.mu.RUnlock()
+
+		value, err := entry.load()
+		if err == nil {
+			err = validateConfig(value)
+		}
+		if err != nil {
+			c.callReloadHooks(key, time.Since(start), err)
+			return errConfigFailed(key, err)
+		}
+
+		if !reflectPkg.DeepEqual(value, current) {
+			values[key] = value
+		}
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	changed := make([]string, 0, len(values))
+	for _, key := range keys {
+		if _, ok := values[key]; ok {
+			changed = append(changed, key)
+		}
+	}
+
+	rebuilt, err := c.internal.Reload(ctx, values)
+	for _, key := range changed {
+		c.callReloadHooks(key, time.Since(start), err)
+	}
+	if err != nil {
+		return errConfigFailed(strings.Join(changed, ", "), err)
+	}
+
+	c.configs.mu.Lock()
+	for key, value := range values {
+		c.configs.entries[key].value = value
+		c.configs.entries[key].err = nil
+	}
+	c.configs.mu.Unlock()
+
+	c.config.logger.Debug("config reloaded", "configs", len(values), "rebuilt", rebuilt)
+	return nil
+}
+
+func (c *Container) WatchConfig(ctx context.Context, opts ...WatchOption) error {
+	cfg := &watchConfig{
+		interval: time.Second,
+		signals:  []os.Signal{syscall.SIGHUP},
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	var signals chan os.Signal
+	if len(cfg.signals) > 0 {
+		signals = make(chan os.Signal, 1)
+		signal.Notify(signals, cfg.signals...)
+		defer signal.Stop(signals)
+	}
+
+	var tick <-chan time.Time
+	mtimes := make(map[string]time.Time, len(cfg.files))
+	if len(cfg.files) > 0 {
+		for _, path := range cfg.files {
+			mtimes[path] = modTime(path)
+		}
+		ticker := time.NewTicker(cfg.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-signals:
+			c.reloadAndLog(ctx, "signal")
+		case <-tick:
+			changed := false
+			for path, previous := range mtimes {
+				if current := modTime(path); !current.Equal(previous) {
+					mtimes[path] = current
+					changed = true
+				}
+			}
+			if changed {
+				c.reloadAndLog(ctx, "file change")
+			}
+		}
+	}
+}
+
+func (c *Container) reloadAndLog(ctx context.Context, trigger string) {
+	if err := c.ReloadConfig(ctx); err != nil {
+		c.config.logger.Warn("config reload failed, keeping previous values", "trigger", trigger, "error", err)
+	}
+}
+
+func (c *Container) callReloadHooks(key string, duration time.Duration, err error) {
+	for _, hook := range c.config.onReload {
+		hook(key, duration, err)
+	}
+}
+
+func modTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,261 @@
+package needle_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/danpasecinic/needle"
+)
+
+type ReloadableServer struct {
+	Port int
+}
+
+func provideReloadableServer(c *needle.Container) error {
+	return needle.ProvideFunc[*ReloadableServer](
+		c, func(cfg *AppConfig) (*ReloadableServer, error) {
+			if cfg.Port > 9000 {
+				return nil, errors.New("port out of range")
+			}
+			return &ReloadableServer{Port: cfg.Port}, nil
+		},
+	)
+}
+
+func updateConfigFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to update config: %v", err)
+	}
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatalf("failed to touch config: %v", err)
+	}
+}
+
+func TestReloadConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"rebuilds dependents", func(t *testing.T) {
+			t.Parallel()
+
+			path := writeConfigFile(t, `{"port": 8000, "database": {"url": "db"}}`)
+
+			var mu sync.Mutex
+			var events []string
+			c := needle.New(
+				needle.WithReloadObserver(
+					func(key string, duration time.Duration, err error) {
+						mu.Lock()
+						defer mu.Unlock()
+						events = append(events, key)
+					},
+				),
+			)
+			_ = needle.ProvideConfig[*AppConfig](c, needle.FromJSON(path))
+			_ = provideReloadableServer(c)
+
+			before := needle.MustInvoke[*ReloadableServer](c)
+
+			updateConfigFile(t, path, `{"port": 8001, "database": {"url": "db"}}`)
+			if err := c.ReloadConfig(context.Background()); err != nil {
+				t.Fatalf("ReloadConfig failed: %v", err)
+			}
+
+			after := needle.MustInvoke[*ReloadableServer](c)
+			if after == before || after.Port != 8001 {
+				t.Errorf("expected rebuilt server on port 8001, got %+v", after)
+			}
+			if needle.MustInvoke[*AppConfig](c).Port != 8001 {
+				t.Error("expected config value to be replaced")
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if len(events) != 1 || events[0] != "*github.com/danpasecinic/needle_test.AppConfig" {
+				t.Errorf("expected one reload event, got %v", events)
+			}
+		},
+	)
+
+	t.Run(
+		"keeps old values when a provider fails", func(t *testing.T) {
+			t.Parallel()
+
+			path := writeConfigFile(t, `{"port": 8000, "database": {"url": "db"}}`)
+
+			var failures int
+			c := needle.New(
+				needle.WithReloadObserver(
+					func(key string, duration time.Duration, err error) {
+						if err != nil {
+							failures++
+						}
+					},
+				),
+			)
+			_ = needle.ProvideConfig[*AppConfig](c, needle.FromJSON(path))
+			_ = provideReloadableServer(c)
+			before := needle.MustInvoke[*ReloadableServer](c)
+
+			updateConfigFile(t, path, `{"port": 9999, "database": {"url": "db"}}`)
+			if err := c.ReloadConfig(context.Background()); !needle.IsConfigFailed(err) {
+				t.Fatalf("expected config error, got %v", err)
+			}
+
+			if needle.MustInvoke[*ReloadableServer](c) != before {
+				t.Error("expected previous server instance to be kept")
+			}
+			if needle.MustInvoke[*AppConfig](c).Port != 8000 {
+				t.Error("expected previous config to be kept")
+			}
+			if failures != 1 {
+				t.Errorf("expected one failed reload event, got %d", failures)
+			}
+		},
+	)
+
+	t.Run(
+		"restarts started services with new instances", func(t *testing.T) {
+			t.Parallel()
+
+			path := writeConfigFile(t, `{"port": 8000, "database": {"url": "db"}}`)
+
+			c := needle.New()
+			var events []string
+			record := func(event string) needle.Hook {
+				return func(ctx context.Context) error {
+					events = append(events, fmt.Sprintf("%s:%d", event, needle.MustInvoke[*ReloadableServer](c).Port))
+					return nil
+				}
+			}
+			_ = needle.ProvideConfig[*AppConfig](c, needle.FromJSON(path))
+			_ = needle.ProvideFunc[*ReloadableServer](
+				c, func(cfg *AppConfig) *ReloadableServer {
+					return &ReloadableServer{Port: cfg.Port}
+				},
+				needle.WithOnStart(record("start")),
+				needle.WithOnStop(record("stop")),
+			)
+
+			if err := c.Start(context.Background()); err != nil {
+				t.Fatalf("Start failed: %v", err)
+			}
+			updateConfigFile(t, path, `{"port": 8001, "database": {"url": "db"}}`)
+			if err := c.ReloadConfig(context.Background()); err != nil {
+				t.Fatalf("ReloadConfig failed: %v", err)
+			}
+
+			expected := []string{"start:8000", "stop:8000", "start:8001"}
+			if !slices.Equal(events, expected) {
+				t.Errorf("expected events %v, got %v", expected, events)
+			}
+		},
+	)
+
+	t.Run(
+		"serves previous instances while rebuilding", func(t *testing.T) {
+			t.Parallel()
+
+			path := writeConfigFile(t, `{"port": 8000, "database": {"url": "db"}}`)
+
+			building := make(chan struct{})
+			release := make(chan struct{})
+			c := needle.New()
+			_ = needle.ProvideConfig[*AppConfig](c, needle.FromJSON(path))
+			_ = needle.ProvideFunc[*ReloadableServer](
+				c, func(cfg *AppConfig) *ReloadableServer {
+					if cfg.Port == 8001 {
+						close(building)
+						<-release
+					}
+					return &ReloadableServer{Port: cfg.Port}
+				},
+			)
+			_ = needle.MustInvoke[*ReloadableServer](c)
+
+			updateConfigFile(t, path, `{"port": 8001, "database": {"url": "db"}}`)
+			done := make(chan error, 1)
+			go func() {
+				done <- c.ReloadConfig(context.Background())
+			}()
+
+			<-building
+			if port := needle.MustInvoke[*ReloadableServer](c).Port; port != 8000 {
+				t.Errorf("expected previous server during rebuild, got port %d", port)
+			}
+			if port := needle.MustInvoke[*AppConfig](c).Port; port != 8000 {
+				t.Errorf("expected previous config during rebuild, got port %d", port)
+			}
+			close(release)
+
+			if err := <-done; err != nil {
+				t.Fatalf("ReloadConfig failed: %v", err)
+			}
+			if port := needle.MustInvoke[*ReloadableServer](c).Port; port != 8001 {
+				t.Errorf("expected rebuilt server, got port %d", port)
+			}
+		},
+	)
+
+	t.Run(
+		"keeps old values when validation fails", func(t *testing.T) {
+			t.Parallel()
+
+			path := writeConfigFile(t, `{"port": 8000, "database": {"url": "db"}}`)
+
+			c := needle.New()
+			_ = needle.ProvideConfig[*AppConfig](c, needle.FromJSON(path))
+
+			updateConfigFile(t, path, `{"port": 8001}`)
+			if err := c.ReloadConfig(context.Background()); err == nil {
+				t.Fatal("expected validation error")
+			}
+			if needle.MustInvoke[*AppConfig](c).Port != 8000 {
+				t.Error("expected previous config to be kept")
+			}
+		},
+	)
+}
+
+func TestWatchConfig(t *testing.T) {
+	t.Parallel()
+
+	path := writeConfigFile(t, `{"port": 8000, "database": {"url": "db"}}`)
+
+	c := needle.New()
+	_ = needle.ProvideConfig[*AppConfig](c, needle.FromJSON(path))
+	_ = provideReloadableServer(c)
+	_ = needle.MustInvoke[*ReloadableServer](c)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- c.WatchConfig(ctx, needle.WatchFiles(path), needle.WatchInterval(5*time.Millisecond), needle.WatchSignals())
+	}()
+
+	updateConfigFile(t, path, `{"port": 8002, "database": {"url": "db"}}`)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for needle.MustInvoke[*ReloadableServer](c).Port != 8002 {
+		if time.Now().After(deadline) {
+			t.Fatal("config change was not picked up")
+		}
+		touched := time.Now()
+		_ = os.Chtimes(path, touched, touched)
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("WatchConfig returned error: %v", err)
+	}
+}
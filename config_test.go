@@ -0,0 +1,250 @@
+package needle_test
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danpasecinic/needle"
+)
+
+type AppConfig struct {
+	Host     string        `json:"host" default:"localhost"`
+	Port     int           `json:"port" default:"8080"`
+	Timeout  time.Duration `json:"timeout" default:"5s"`
+	Debug    bool          `json:"debug"`
+	Tags     []string      `json:"tags" default:"api, web"`
+	Password string        `json:"password" sensitive:"true"`
+	Database struct {
+		URL string `json:"url" required:"true"`
+	} `json:"database"`
+}
+
+type LimitsConfig struct {
+	Min int `default:"10"`
+	Max int `default:"5"`
+}
+
+func (l LimitsConfig) Validate() error {
+	if l.Min > l.Max {
+		return errors.New("min exceeds max")
+	}
+	return nil
+}
+
+type WorkerConfig struct {
+	Port    int `json:"port"`
+	Workers int `json:"workers" default:"1"`
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestProvideConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"applies defaults", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			if err := needle.ProvideConfig[*AppConfig](c); err != nil {
+				t.Fatalf("ProvideConfig failed: %v", err)
+			}
+
+			cfg := needle.MustInvoke[*AppConfig](c)
+			if cfg.Host != "localhost" || cfg.Port != 8080 || cfg.Timeout != 5*time.Second {
+				t.Errorf("unexpected defaults: %+v", cfg)
+			}
+			if len(cfg.Tags) != 2 || cfg.Tags[1] != "web" {
+				t.Errorf("unexpected tags: %v", cfg.Tags)
+			}
+		},
+	)
+
+	t.Run(
+		"layers json and flags", func(t *testing.T) {
+			t.Parallel()
+
+			path := writeConfigFile(t, `{"port": 9000, "database": {"url": "postgres://db"}}`)
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+
+			c := needle.New()
+			err := needle.ProvideConfig[*AppConfig](
+				c,
+				needle.FromJSON(path),
+				needle.FromFlags(fs, []string{"-port", "9100", "-debug"}),
+			)
+			if err != nil {
+				t.Fatalf("ProvideConfig failed: %v", err)
+			}
+
+			cfg := needle.MustInvoke[*AppConfig](c)
+			if cfg.Port != 9100 {
+				t.Errorf("expected flag to override json, got port %d", cfg.Port)
+			}
+			if !cfg.Debug {
+				t.Error("expected boolean flag to be set")
+			}
+			if cfg.Database.URL != "postgres://db" || cfg.Host != "localhost" {
+				t.Errorf("unexpected layered config: %+v", cfg)
+			}
+			if err := c.Validate(); err != nil {
+				t.Errorf("Validate failed: %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"shares a flag set between configs", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			fs := flag.NewFlagSet("app", flag.ContinueOnError)
+			needle.DefineConfigFlags[*AppConfig](fs)
+			needle.DefineConfigFlags[WorkerConfig](fs)
+			args := []string{"-port", "9100", "-workers", "4"}
+
+			if err := needle.ProvideConfig[*AppConfig](c, needle.FromFlags(fs, args)); err != nil {
+				t.Fatalf("ProvideConfig failed: %v", err)
+			}
+			if err := needle.ProvideConfig[WorkerConfig](c, needle.FromFlags(fs, args)); err != nil {
+				t.Fatalf("ProvideConfig failed: %v", err)
+			}
+
+			if port := needle.MustInvoke[*AppConfig](c).Port; port != 9100 {
+				t.Errorf("expected shared port flag, got %d", port)
+			}
+			workers := needle.MustInvoke[WorkerConfig](c)
+			if workers.Port != 9100 || workers.Workers != 4 {
+				t.Errorf("unexpected worker config: %+v", workers)
+			}
+		},
+	)
+
+	t.Run(
+		"reads a flag set parsed by the caller", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			fs := flag.NewFlagSet("app", flag.ContinueOnError)
+			needle.DefineConfigFlags[WorkerConfig](fs)
+			if err := fs.Parse([]string{"-workers", "8"}); err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+
+			if err := needle.ProvideConfig[WorkerConfig](c, needle.FromFlags(fs, nil)); err != nil {
+				t.Fatalf("ProvideConfig failed: %v", err)
+			}
+			if workers := needle.MustInvoke[WorkerConfig](c).Workers; workers != 8 {
+				t.Errorf("expected 8 workers, got %d", workers)
+			}
+		},
+	)
+
+	t.Run(
+		"registers struct values", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			needle.MustProvideConfig[LimitsConfig](c)
+
+			if needle.MustInvoke[LimitsConfig](c).Min != 10 {
+				t.Error("expected struct value config")
+			}
+		},
+	)
+
+	t.Run(
+		"reports load errors", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			err := needle.ProvideConfig[*AppConfig](c, needle.FromJSON(writeConfigFile(t, `{"port": "x"}`)))
+			if !needle.IsConfigFailed(err) {
+				t.Errorf("expected config error, got %v", err)
+			}
+			if needle.Has[*AppConfig](c) {
+				t.Error("config should not be registered after a load error")
+			}
+		},
+	)
+}
+
+func TestProvideConfigEnv(t *testing.T) {
+	t.Setenv("APP_PORT", "7000")
+	t.Setenv("APP_DATABASE_URL", "postgres://env")
+	t.Setenv("APP_TAGS", "a,b,c")
+
+	c := needle.New()
+	if err := needle.ProvideConfig[*AppConfig](c, needle.FromEnv("APP")); err != nil {
+		t.Fatalf("ProvideConfig failed: %v", err)
+	}
+
+	cfg := needle.MustInvoke[*AppConfig](c)
+	if cfg.Port != 7000 || cfg.Database.URL != "postgres://env" || len(cfg.Tags) != 3 {
+		t.Errorf("unexpected env config: %+v", cfg)
+	}
+}
+
+func TestConfigValidation(t *testing.T) {
+	t.Parallel()
+
+	c := needle.New()
+	_ = needle.ProvideConfig[*AppConfig](c)
+	_ = needle.ProvideConfig[LimitsConfig](c)
+
+	err := c.Validate()
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	if !strings.Contains(err.Error(), "database.url is required") {
+		t.Errorf("expected required field error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "min exceeds max") {
+		t.Errorf("expected Validate method error, got %v", err)
+	}
+}
+
+func TestConfigRedaction(t *testing.T) {
+	t.Parallel()
+
+	path := writeConfigFile(t, `{"password": "hunter2", "database": {"url": "postgres://db"}}`)
+
+	c := needle.New()
+	if err := needle.ProvideConfig[*AppConfig](c, needle.FromJSON(path)); err != nil {
+		t.Fatalf("ProvideConfig failed: %v", err)
+	}
+
+	var found bool
+	for _, svc := range c.Graph().Services {
+		if svc.Config == nil {
+			continue
+		}
+		found = true
+		if svc.Config["password"] != "[redacted]" {
+			t.Errorf("expected redacted password, got %q", svc.Config["password"])
+		}
+		if svc.Config["database.url"] != "postgres://db" {
+			t.Errorf("expected database url, got %q", svc.Config["database.url"])
+		}
+	}
+	if !found {
+		t.Fatal("expected config details in graph")
+	}
+
+	if text := c.SprintGraph(); strings.Contains(text, "hunter2") || !strings.Contains(text, "password=[redacted]") {
+		t.Errorf("expected redacted graph output, got: %s", text)
+	}
+}
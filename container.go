@@ -3,9 +3,7 @@ package needle
 import (
 	"context"
 	"log/slog"
-	"os"
-	"os/signal"
-	"syscall"
+	"sync/atomic"
 	"time"
 
 	"github.com/danpasecinic/needle/internal/container"
@@ -15,6 +13,12 @@ type Container struct {
 	internal *container.Container
 	config   *containerConfig
 	resolver *resolverAdapter
+	profiles *profileState
+	modules  *moduleIndex
+	configs  *configState
+	runners  *runnerState
+	draining *atomic.Bool
+	scope    *moduleScope
 }
 
 type containerConfig struct {
@@ -23,8 +27,16 @@ type containerConfig struct {
 	onProvide       []ProvideHook
 	onStart         []StartHook
 	onStop          []StopHook
+	onReload        []ReloadHook
+	onState         []StateHook
+	startupTimeout  time.Duration
 	shutdownTimeout time.Duration
 	parallel        bool
+	maxConcurrency  int
+	restartStrategy RestartStrategy
+	phases          []string
+	profiles        []string
+	combinations    [][]string
 }
 
 func newContainer(opts ...Option) *Container {
@@ -37,8 +49,12 @@ func newContainer(opts ...Option) *Container {
 	}
 
 	internalCfg := &container.Config{
-		Logger:   cfg.logger,
-		Parallel: cfg.parallel,
+		Logger:           cfg.logger,
+		Parallel:         cfg.parallel,
+		MaxConcurrency:   cfg.maxConcurrency,
+		RebuildOnRestart: cfg.restartStrategy == RebuildInstances,
+		ShutdownTimeout:  cfg.shutdownTimeout,
+		Phases:           cfg.phases,
 	}
 
 	for _, h := range cfg.onResolve {
@@ -57,19 +73,44 @@ func newContainer(opts ...Option) *Container {
 		hook := h
 		internalCfg.OnStop = append(internalCfg.OnStop, container.StopHook(hook))
 	}
+	for _, h := range cfg.onState {
+		hook := h
+		internalCfg.OnState = append(
+			internalCfg.OnState, func(from, to container.State) {
+				hook(State(from), State(to))
+			},
+		)
+	}
 
 	c := &Container{
 		internal: container.New(internalCfg),
 		config:   cfg,
+		profiles: newProfileState(cfg.profiles, cfg.combinations),
+		modules:  newModuleIndex(),
+		configs:  newConfigState(),
+		runners:  newRunnerState(),
+		draining: new(atomic.Bool),
 	}
 	c.resolver = &resolverAdapter{container: c}
 	return c
 }
 
 func (c *Container) Validate() error {
+	if err := c.validateVisibility(); err != nil {
+		return errValidationFailed(err)
+	}
+	if err := c.validateRequirements(); err != nil {
+		return errValidationFailed(err)
+	}
+	if err := c.validateConfigs(); err != nil {
+		return errValidationFailed(err)
+	}
 	if err := c.internal.Validate(); err != nil {
 		return errValidationFailed(err)
 	}
+	if err := c.validateCombinations(); err != nil {
+		return errValidationFailed(err)
+	}
 	return nil
 }
 
@@ -82,9 +123,17 @@ func (c *Container) Keys() []string {
 }
 
 func (c *Container) Start(ctx context.Context) error {
+	if c.config.startupTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.startupTimeout)
+		defer cancel()
+	}
 	if err := c.internal.Start(ctx); err != nil {
 		return errStartupFailed("container", err)
 	}
+	if err := c.startRunners(ctx); err != nil {
+		return errStartupFailed("container", err)
+	}
 	return nil
 }
 
@@ -94,29 +143,19 @@ func (c *Container) Stop(ctx context.Context) error {
 		ctx, cancel = context.WithTimeout(ctx, c.config.shutdownTimeout)
 		defer cancel()
 	}
-	if err := c.internal.Stop(ctx); err != nil {
-		return errShutdownFailed("container", err)
-	}
-	return nil
+	runnerErrs := c.stopRunners(ctx)
+	return newShutdownError(runnerErrs, c.internal.Stop(ctx))
 }
 
-func (c *Container) Run(ctx context.Context) error {
-	if err := c.Start(ctx); err != nil {
+func (c *Container) Restart(ctx context.Context) error {
+	if err := c.Stop(ctx); err != nil {
 		return err
 	}
+	return c.Start(ctx)
+}
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-
-	select {
-	case <-ctx.Done():
-	case <-quit:
-	}
-
-	signal.Stop(quit)
-	close(quit)
-
-	return c.Stop(context.Background())
+func (c *Container) State() State {
+	return State(c.internal.State())
 }
 
 func errValidationFailed(cause error) *Error {
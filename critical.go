@@ -0,0 +1,72 @@
+package needle
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+type CriticalPath struct {
+	Services  []string
+	Durations []time.Duration
+	Total     time.Duration
+}
+
+type LazyEstimate struct {
+	Service    string
+	Current    time.Duration
+	Estimated  time.Duration
+	Saving     time.Duration
+	RequiredBy []string
+}
+
+func (c *Container) CriticalPath() (CriticalPath, error) {
+	durations := c.internal.StartDurations()
+
+	path, total, err := c.internal.Graph().CriticalPath(durations)
+	if err != nil {
+		return CriticalPath{}, fmt.Errorf("failed to compute critical path: %w", err)
+	}
+
+	result := CriticalPath{
+		Services:  path,
+		Durations: make([]time.Duration, len(path)),
+		Total:     total,
+	}
+	for i, key := range path {
+		result.Durations[i] = durations[key]
+	}
+	return result, nil
+}
+
+func (c *Container) EstimateLazy(key string) (LazyEstimate, error) {
+	if !c.internal.Has(key) {
+		return LazyEstimate{}, fmt.Errorf("service not found: %s", key)
+	}
+
+	g := c.internal.Graph()
+	durations := c.internal.StartDurations()
+
+	_, current, err := g.CriticalPath(durations)
+	if err != nil {
+		return LazyEstimate{}, fmt.Errorf("failed to compute critical path: %w", err)
+	}
+
+	estimate := LazyEstimate{Service: key, Current: current, Estimated: current}
+	for _, dependent := range g.GetDependents(key) {
+		if _, started := durations[dependent]; started {
+			estimate.RequiredBy = append(estimate.RequiredBy, dependent)
+		}
+	}
+	if len(estimate.RequiredBy) > 0 {
+		sort.Strings(estimate.RequiredBy)
+		return estimate, nil
+	}
+
+	delete(durations, key)
+	if _, estimate.Estimated, err = g.CriticalPath(durations); err != nil {
+		return LazyEstimate{}, fmt.Errorf("failed to compute critical path: %w", err)
+	}
+	estimate.Saving = current - estimate.Estimated
+	return estimate, nil
+}
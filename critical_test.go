@@ -0,0 +1,83 @@
+package needle_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danpasecinic/needle"
+)
+
+type SlowDB struct{}
+type SlowRepo struct{}
+type FastCache struct{}
+type FastAPI struct{}
+
+func sleepHook(d time.Duration) needle.Hook {
+	return func(ctx context.Context) error {
+		time.Sleep(d)
+		return nil
+	}
+}
+
+func TestCriticalPath(t *testing.T) {
+	t.Parallel()
+
+	c := needle.New(needle.WithParallel())
+	_ = needle.ProvideValue(c, &SlowDB{}, needle.WithOnStart(sleepHook(60*time.Millisecond)))
+	_ = needle.ProvideFunc[*SlowRepo](
+		c, func(*SlowDB) *SlowRepo { return &SlowRepo{} }, needle.WithOnStart(sleepHook(30*time.Millisecond)),
+	)
+	_ = needle.ProvideValue(c, &FastCache{}, needle.WithOnStart(sleepHook(time.Millisecond)))
+	_ = needle.ProvideFunc[*FastAPI](c, func(*FastCache) *FastAPI { return &FastAPI{} })
+
+	if path, err := c.CriticalPath(); err != nil || len(path.Services) != 0 {
+		t.Errorf("expected empty path before Start, got %+v (%v)", path, err)
+	}
+
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	path, err := c.CriticalPath()
+	if err != nil {
+		t.Fatalf("CriticalPath failed: %v", err)
+	}
+	if len(path.Services) != 2 || !strings.HasSuffix(path.Services[0], ".SlowDB") ||
+		!strings.HasSuffix(path.Services[1], ".SlowRepo") {
+		t.Fatalf("unexpected critical path: %v", path.Services)
+	}
+	if path.Total < 90*time.Millisecond || path.Durations[0] < 60*time.Millisecond {
+		t.Errorf("unexpected durations: %+v", path)
+	}
+
+	text := c.SprintGraph()
+	if !strings.Contains(text, "critical path") || !strings.Contains(text, "(critical ") {
+		t.Errorf("expected critical path in graph output, got: %s", text)
+	}
+	if !strings.Contains(c.SprintGraphDOT(), "color=red, penwidth=2") {
+		t.Errorf("expected highlighted edges in DOT output, got: %s", c.SprintGraphDOT())
+	}
+
+	t.Run(
+		"estimates making a service lazy", func(t *testing.T) {
+			repo, err := c.EstimateLazy("*github.com/danpasecinic/needle_test.SlowRepo")
+			if err != nil {
+				t.Fatalf("EstimateLazy failed: %v", err)
+			}
+			if repo.Saving < 20*time.Millisecond || repo.Estimated >= repo.Current {
+				t.Errorf("expected a saving for the repo, got %+v", repo)
+			}
+
+			db, _ := c.EstimateLazy("*github.com/danpasecinic/needle_test.SlowDB")
+			if db.Saving != 0 || len(db.RequiredBy) != 1 {
+				t.Errorf("expected no saving for a required service, got %+v", db)
+			}
+
+			if _, err := c.EstimateLazy("missing"); err == nil {
+				t.Error("expected error for unknown service")
+			}
+		},
+	)
+}
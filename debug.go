@@ -6,43 +6,95 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"time"
 )
 
 type GraphInfo struct {
-	Services []ServiceInfo
+	Services     []ServiceInfo
+	Templates    []string
+	Selections   map[string]string
+	CriticalPath []string
 }
 
 type ServiceInfo struct {
 	Key          string
 	Dependencies []string
+	Deferred     []string
 	Dependents   []string
 	Instantiated bool
 	Scope        string
+	Template     string
+	Binding      string
+	Primary      bool
+	Priority     int
+	Selected     bool
+	Default      bool
+	Overrides    bool
+	Module       string
+	Config       map[string]string
+
+	StartDuration time.Duration
+	Critical      bool
+
+	Inactive       bool
+	InactiveReason string
 }
 
 func (c *Container) Graph() GraphInfo {
-	keys := c.internal.Keys()
-	sort.Strings(keys)
-
+	entries := c.internal.Entries()
 	graph := c.internal.Graph()
-	services := make([]ServiceInfo, 0, len(keys))
+	selections := c.internal.Selections()
+
+	selected := make(map[string]bool, len(selections))
+	for _, winner := range selections {
+		selected[winner] = true
+	}
+
+	durations := c.internal.StartDurations()
+	criticalPath, _, _ := graph.CriticalPath(durations)
+	critical := make(map[string]bool, len(criticalPath))
+	for _, key := range criticalPath {
+		critical[key] = true
+	}
 
-	for _, key := range keys {
-		deps := graph.GetDependencies(key)
-		dependents := graph.GetDependents(key)
-		_, instantiated := c.internal.GetInstance(key)
+	services := make([]ServiceInfo, 0, len(entries))
 
+	for _, entry := range entries {
 		services = append(
 			services, ServiceInfo{
-				Key:          key,
-				Dependencies: deps,
-				Dependents:   dependents,
-				Instantiated: instantiated,
+				Key:          entry.Key,
+				Dependencies: graph.GetDependencies(entry.Key),
+				Deferred:     graph.GetDeferred(entry.Key),
+				Dependents:   graph.GetDependents(entry.Key),
+				Instantiated: entry.Instantiated,
+				Scope:        entry.Scope.String(),
+				Template:     entry.Template,
+				Binding:      entry.Binding,
+				Primary:      entry.Primary,
+				Priority:     entry.Priority,
+				Selected:     selected[entry.Key],
+				Default:      entry.Default,
+				Overrides:    entry.Overrides,
+				Module:       c.moduleOf(entry.Key),
+				Config:       c.describeConfig(entry.Key),
+
+				StartDuration: durations[entry.Key],
+				Critical:      critical[entry.Key],
 			},
 		)
 	}
 
-	return GraphInfo{Services: services}
+	services = append(services, c.inactiveServices()...)
+	sort.SliceStable(
+		services, func(i, j int) bool {
+			return services[i].Key < services[j].Key
+		},
+	)
+
+	templates := c.internal.Templates()
+	sort.Strings(templates)
+
+	return GraphInfo{Services: services, Templates: templates, Selections: selections, CriticalPath: criticalPath}
 }
 
 func (c *Container) PrintGraph() {
@@ -57,18 +109,98 @@ func (c *Container) FprintGraph(w io.Writer) {
 		return
 	}
 
-	for _, svc := range info.Services {
-		status := "○"
-		if svc.Instantiated {
-			status = "●"
+	for _, group := range groupByModule(info.Services) {
+		indent := ""
+		if group.module != "" {
+			_, _ = fmt.Fprintf(w, "[%s]\n", group.module)
+			indent = "  "
+		}
+		for _, svc := range group.services {
+			_, _ = fmt.Fprintln(w, indent+serviceLine(svc))
 		}
+	}
+
+	if len(info.CriticalPath) > 0 {
+		var total time.Duration
+		for _, svc := range info.Services {
+			if svc.Critical {
+				total += svc.StartDuration
+			}
+		}
+		_, _ = fmt.Fprintf(w, "\ncritical path (%s): %s\n", total, strings.Join(info.CriticalPath, " → "))
+	}
+}
+
+func serviceLine(svc ServiceInfo) string {
+	if svc.Inactive {
+		return fmt.Sprintf("× %s (inactive: %s)", svc.Key, svc.InactiveReason)
+	}
+
+	status := "○"
+	if svc.Instantiated {
+		status = "●"
+	}
+
+	line := status + " " + svc.Key
+	if len(svc.Dependencies) > 0 {
+		line += " ← " + strings.Join(svc.Dependencies, ", ")
+	}
+	if len(svc.Deferred) > 0 {
+		line += " ⇠ " + strings.Join(svc.Deferred, ", ")
+	}
+	if svc.Selected {
+		line += " ★"
+	}
+	if svc.Default {
+		line += " (default)"
+	} else if svc.Overrides {
+		line += " (overrides default)"
+	}
+	if len(svc.Config) > 0 {
+		line += " " + formatConfig(svc.Config)
+	}
+	if svc.Critical {
+		line += fmt.Sprintf(" (critical %s)", svc.StartDuration)
+	}
+	return line
+}
 
-		if len(svc.Dependencies) == 0 {
-			_, _ = fmt.Fprintf(w, "%s %s\n", status, svc.Key)
-		} else {
-			_, _ = fmt.Fprintf(w, "%s %s ← %s\n", status, svc.Key, strings.Join(svc.Dependencies, ", "))
+func formatConfig(config map[string]string) string {
+	fields := make([]string, 0, len(config))
+	for field := range config {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for i, field := range fields {
+		fields[i] = field + "=" + config[field]
+	}
+	return "{" + strings.Join(fields, ", ") + "}"
+}
+
+type moduleGroup struct {
+	module   string
+	services []ServiceInfo
+}
+
+func groupByModule(services []ServiceInfo) []moduleGroup {
+	index := make(map[string]int)
+	var groups []moduleGroup
+	for _, svc := range services {
+		i, ok := index[svc.Module]
+		if !ok {
+			i = len(groups)
+			index[svc.Module] = i
+			groups = append(groups, moduleGroup{module: svc.Module})
 		}
+		groups[i].services = append(groups[i].services, svc)
 	}
+	sort.SliceStable(
+		groups, func(i, j int) bool {
+			return groups[i].module < groups[j].module
+		},
+	)
+	return groups
 }
 
 func (c *Container) SprintGraph() string {
@@ -88,21 +220,73 @@ func (c *Container) FprintGraphDOT(w io.Writer) {
 	_, _ = fmt.Fprintln(w, "  rankdir=LR;")
 	_, _ = fmt.Fprintln(w, "  node [shape=box];")
 
-	for _, svc := range info.Services {
-		label := escapeLabel(svc.Key)
-		style := ""
-		if svc.Instantiated {
-			style = ", style=filled, fillcolor=lightblue"
+	for _, group := range groupByModule(info.Services) {
+		indent := "  "
+		if group.module != "" {
+			_, _ = fmt.Fprintf(w, "  subgraph %q {\n", "cluster_"+group.module)
+			_, _ = fmt.Fprintf(w, "    label=%q;\n", group.module)
+			indent = "    "
+		}
+		for _, svc := range group.services {
+			if svc.Inactive {
+				continue
+			}
+			label := escapeLabel(svc.Key)
+			style := ""
+			if svc.Instantiated {
+				style = ", style=filled, fillcolor=lightblue"
+			}
+			if svc.Selected {
+				style += ", peripheries=2"
+			}
+			if svc.Critical {
+				style += ", color=red, penwidth=2"
+			}
+			_, _ = fmt.Fprintf(w, "%s%q [label=%q%s];\n", indent, svc.Key, label, style)
+		}
+		if group.module != "" {
+			_, _ = fmt.Fprintln(w, "  }")
 		}
-		_, _ = fmt.Fprintf(w, "  %q [label=%q%s];\n", svc.Key, label, style)
 	}
 
 	_, _ = fmt.Fprintln(w)
 
+	critical := make(map[[2]string]bool, len(info.CriticalPath))
+	for i := 1; i < len(info.CriticalPath); i++ {
+		critical[[2]string{info.CriticalPath[i], info.CriticalPath[i-1]}] = true
+	}
+
 	for _, svc := range info.Services {
+		if svc.Inactive {
+			continue
+		}
 		for _, dep := range svc.Dependencies {
+			edge := [2]string{svc.Key, dep}
+			highlight := ""
+			if critical[edge] {
+				highlight = ", color=red, penwidth=2"
+				delete(critical, edge)
+			}
+			if dep == svc.Binding {
+				_, _ = fmt.Fprintf(w, "  %q -> %q [arrowhead=empty%s];\n", svc.Key, dep, highlight)
+				continue
+			}
+			if highlight != "" {
+				_, _ = fmt.Fprintf(w, "  %q -> %q [%s];\n", svc.Key, dep, highlight[2:])
+				continue
+			}
 			_, _ = fmt.Fprintf(w, "  %q -> %q;\n", svc.Key, dep)
 		}
+		for _, dep := range svc.Deferred {
+			_, _ = fmt.Fprintf(w, "  %q -> %q [style=dashed];\n", svc.Key, dep)
+		}
+	}
+
+	for i := 1; i < len(info.CriticalPath); i++ {
+		edge := [2]string{info.CriticalPath[i], info.CriticalPath[i-1]}
+		if critical[edge] {
+			_, _ = fmt.Fprintf(w, "  %q -> %q [style=dotted, color=red, penwidth=2];\n", edge[0], edge[1])
+		}
 	}
 
 	_, _ = fmt.Fprintln(w, "}")
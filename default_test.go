@@ -0,0 +1,173 @@
+package needle_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/danpasecinic/needle"
+)
+
+type Tracer interface {
+	Trace(name string) string
+}
+
+type NoopTracer struct{}
+
+func (NoopTracer) Trace(name string) string { return "" }
+
+type AppTracer struct{}
+
+func (AppTracer) Trace(name string) string { return "app:" + name }
+
+func TestProvideDefault(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"default is used without override", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.ProvideDefaultValue[Tracer](c, NoopTracer{})
+
+			if needle.MustInvoke[Tracer](c).Trace("x") != "" {
+				t.Error("expected default tracer")
+			}
+		},
+	)
+
+	t.Run(
+		"override registered after default wins", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.ProvideDefaultValue[Tracer](c, NoopTracer{})
+
+			if err := needle.ProvideValue[Tracer](c, AppTracer{}); err != nil {
+				t.Fatalf("override failed: %v", err)
+			}
+			if needle.MustInvoke[Tracer](c).Trace("x") != "app:x" {
+				t.Error("expected override tracer")
+			}
+		},
+	)
+
+	t.Run(
+		"override registered before default wins", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.ProvideValue[Tracer](c, AppTracer{})
+
+			err := needle.ProvideDefault(
+				c, func(ctx context.Context, r needle.Resolver) (Tracer, error) {
+					return NoopTracer{}, nil
+				},
+			)
+			if err != nil {
+				t.Fatalf("default registration should be skipped silently: %v", err)
+			}
+			if needle.MustInvoke[Tracer](c).Trace("x") != "app:x" {
+				t.Error("expected override tracer")
+			}
+		},
+	)
+
+	t.Run(
+		"two defaults conflict", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.ProvideDefaultValue[Tracer](c, NoopTracer{})
+
+			if err := needle.ProvideDefaultValue[Tracer](c, NoopTracer{}); err == nil {
+				t.Error("expected duplicate default error")
+			}
+		},
+	)
+
+	t.Run(
+		"two overrides conflict", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.ProvideDefaultValue[Tracer](c, NoopTracer{})
+			_ = needle.ProvideValue[Tracer](c, AppTracer{})
+
+			if err := needle.ProvideValue[Tracer](c, AppTracer{}); err == nil {
+				t.Error("expected duplicate registration error")
+			}
+		},
+	)
+
+	t.Run(
+		"default hooks are dropped on override", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			var defaultStarted bool
+			_ = needle.ProvideDefaultValue[Tracer](
+				c, NoopTracer{}, needle.WithOnStart(
+					func(ctx context.Context) error {
+						defaultStarted = true
+						return nil
+					},
+				),
+			)
+			_ = needle.ProvideValue[Tracer](c, AppTracer{})
+
+			if err := c.Start(context.Background()); err != nil {
+				t.Fatalf("Start failed: %v", err)
+			}
+			_ = c.Stop(context.Background())
+
+			if defaultStarted {
+				t.Error("default OnStart hook should not run after override")
+			}
+		},
+	)
+
+	t.Run(
+		"default bindings follow defaults", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.ProvideDefaultValue(c, &AppTracer{}, needle.As[Tracer]())
+			_ = needle.ProvideValue[Tracer](c, NoopTracer{})
+
+			if needle.MustInvoke[Tracer](c).Trace("x") != "" {
+				t.Error("expected explicit registration to override default binding")
+			}
+		},
+	)
+}
+
+func TestDefaultOverrideInGraph(t *testing.T) {
+	t.Parallel()
+
+	c := needle.New()
+	_ = needle.ProvideDefaultValue[Tracer](c, NoopTracer{})
+	_ = needle.ProvideValue[Tracer](c, AppTracer{})
+	_ = needle.ProvideDefaultValue(c, &Config{})
+
+	var overridden, kept bool
+	for _, svc := range c.Graph().Services {
+		if strings.HasSuffix(svc.Key, "Tracer") {
+			overridden = svc.Overrides && !svc.Default
+		}
+		if strings.HasSuffix(svc.Key, "Config") {
+			kept = svc.Default
+		}
+	}
+	if !overridden {
+		t.Error("expected tracer override to be recorded")
+	}
+	if !kept {
+		t.Error("expected config default to be recorded")
+	}
+
+	output := c.SprintGraph()
+	if !strings.Contains(output, "(overrides default)") || !strings.Contains(output, "(default)") {
+		t.Errorf("expected override markers in graph, got: %s", output)
+	}
+}
@@ -0,0 +1,173 @@
+package needle
+
+import (
+	"context"
+	"fmt"
+	reflectPkg "reflect"
+	"sync"
+
+	"github.com/danpasecinic/needle/internal/reflect"
+)
+
+type deferredHandle interface {
+	targetKey() string
+	bind(c *Container, key string)
+}
+
+var deferredHandleType = reflectPkg.TypeOf((*deferredHandle)(nil)).Elem()
+
+type Lazy[T any] struct {
+	state *lazyState[T]
+}
+
+type lazyState[T any] struct {
+	mu       sync.Mutex
+	c        *Container
+	key      string
+	value    T
+	resolved bool
+}
+
+func (l Lazy[T]) Get(ctx context.Context) (T, error) {
+	var zero T
+	if l.state == nil {
+		return zero, errResolutionFailed(reflect.TypeName[T](), fmt.Errorf("lazy handle is not bound to a container"))
+	}
+
+	s := l.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.resolved {
+		return s.value, nil
+	}
+
+	instance, err := s.c.internal.Resolve(ctx, s.key)
+	if err != nil {
+		return zero, errResolutionFailed(s.key, err)
+	}
+
+	typed, ok := instance.(T)
+	if !ok {
+		return zero, errResolutionFailed(s.key, errTypeMismatch[T](instance))
+	}
+
+	s.value = typed
+	s.resolved = true
+	return typed, nil
+}
+
+func (l Lazy[T]) MustGet(ctx context.Context) T {
+	v, err := l.Get(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (l Lazy[T]) Resolved() bool {
+	if l.state == nil {
+		return false
+	}
+	l.state.mu.Lock()
+	defer l.state.mu.Unlock()
+	return l.state.resolved
+}
+
+func (l *Lazy[T]) targetKey() string {
+	return reflect.TypeKey[T]()
+}
+
+func (l *Lazy[T]) bind(c *Container, key string) {
+	l.state = &lazyState[T]{c: c, key: key}
+}
+
+type Factory[T any] struct {
+	c   *Container
+	key string
+}
+
+func (f Factory[T]) New(ctx context.Context) (T, error) {
+	var zero T
+	if f.c == nil {
+		return zero, errResolutionFailed(reflect.TypeName[T](), fmt.Errorf("factory is not bound to a container"))
+	}
+
+	instance, err := f.c.internal.Create(ctx, f.key)
+	if err != nil {
+		return zero, errResolutionFailed(f.key, err)
+	}
+
+	typed, ok := instance.(T)
+	if !ok {
+		return zero, errResolutionFailed(f.key, errTypeMismatch[T](instance))
+	}
+
+	return typed, nil
+}
+
+func (f Factory[T]) MustNew(ctx context.Context) T {
+	v, err := f.New(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (f *Factory[T]) targetKey() string {
+	return reflect.TypeKey[T]()
+}
+
+func (f *Factory[T]) bind(c *Container, key string) {
+	f.c = c
+	f.key = key
+}
+
+func InvokeLazy[T any](c *Container) Lazy[T] {
+	return InvokeLazyNamed[T](c, "")
+}
+
+func InvokeLazyNamed[T any](c *Container, name string) Lazy[T] {
+	var l Lazy[T]
+	l.bind(c, deferredKey[T](name))
+	return l
+}
+
+func InvokeFactory[T any](c *Container) Factory[T] {
+	return InvokeFactoryNamed[T](c, "")
+}
+
+func InvokeFactoryNamed[T any](c *Container, name string) Factory[T] {
+	var f Factory[T]
+	f.bind(c, deferredKey[T](name))
+	return f
+}
+
+func deferredKey[T any](name string) string {
+	if name == "" {
+		return reflect.TypeKey[T]()
+	}
+	return reflect.TypeKeyNamed[T](name)
+}
+
+func isDeferred(t reflectPkg.Type) bool {
+	return t != nil && t.Kind() == reflectPkg.Struct && reflectPkg.PointerTo(t).Implements(deferredHandleType)
+}
+
+func deferredTargetKey(t reflectPkg.Type, name string) string {
+	key := reflectPkg.New(t).Interface().(deferredHandle).targetKey()
+	if name != "" {
+		key += "#" + name
+	}
+	return key
+}
+
+func newDeferred(c *Container, t reflectPkg.Type, key string) reflectPkg.Value {
+	ptr := reflectPkg.New(t)
+	ptr.Interface().(deferredHandle).bind(c, key)
+	return ptr.Elem()
+}
+
+func errTypeMismatch[T any](instance any) error {
+	return fmt.Errorf("type mismatch: expected %s, got %T", reflect.TypeName[T](), instance)
+}
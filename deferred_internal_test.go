@@ -0,0 +1,27 @@
+package needle
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestDeferred_TypeMismatch(t *testing.T) {
+	t.Parallel()
+
+	c := New()
+	key := deferredKey[*testCounter]("")
+	if err := c.internal.RegisterValue(key, "not a counter"); err != nil {
+		t.Fatalf("RegisterValue failed: %v", err)
+	}
+
+	_, err := InvokeLazy[*testCounter](c).Get(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "expected *needle.testCounter, got string") {
+		t.Errorf("expected type mismatch from Lazy, got %v", err)
+	}
+
+	_, err = InvokeFactory[*testCounter](c).New(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "expected *needle.testCounter, got string") {
+		t.Errorf("expected type mismatch from Factory, got %v", err)
+	}
+}
@@ -0,0 +1,276 @@
+package needle_test
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/danpasecinic/needle"
+)
+
+type CycleA struct {
+	B needle.Lazy[*CycleB]
+}
+
+type CycleB struct {
+	A *CycleA
+}
+
+type LazyConsumer struct {
+	Config  needle.Lazy[*Config]          `needle:""`
+	Primary needle.Lazy[*Database]        `needle:"primary"`
+	Conns   needle.Factory[*TestDatabase] `needle:""`
+}
+
+func TestLazy(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"resolves on first get", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			var calls atomic.Int32
+
+			_ = needle.Provide(
+				c, func(ctx context.Context, r needle.Resolver) (*Config, error) {
+					calls.Add(1)
+					return &Config{Port: 8080}, nil
+				},
+			)
+
+			lazy := needle.InvokeLazy[*Config](c)
+			if calls.Load() != 0 {
+				t.Fatal("provider should not run before Get")
+			}
+			if lazy.Resolved() {
+				t.Error("lazy should not be resolved before Get")
+			}
+
+			cfg, err := lazy.Get(context.Background())
+			if err != nil {
+				t.Fatalf("Get failed: %v", err)
+			}
+			if cfg.Port != 8080 {
+				t.Errorf("expected port 8080, got %d", cfg.Port)
+			}
+			if !lazy.Resolved() {
+				t.Error("lazy should be resolved after Get")
+			}
+		},
+	)
+
+	t.Run(
+		"caches transient targets", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			var calls atomic.Int32
+
+			_ = needle.Provide(
+				c, func(ctx context.Context, r needle.Resolver) (*Config, error) {
+					calls.Add(1)
+					return &Config{}, nil
+				}, needle.WithScope(needle.Transient),
+			)
+
+			lazy := needle.InvokeLazy[*Config](c)
+			first := lazy.MustGet(context.Background())
+			second := lazy.MustGet(context.Background())
+
+			if first != second {
+				t.Error("expected cached instance")
+			}
+			if calls.Load() != 1 {
+				t.Errorf("expected 1 provider call, got %d", calls.Load())
+			}
+		},
+	)
+
+	t.Run(
+		"unbound handle returns error", func(t *testing.T) {
+			t.Parallel()
+
+			var lazy needle.Lazy[*Config]
+			if _, err := lazy.Get(context.Background()); err == nil {
+				t.Error("expected error for unbound lazy handle")
+			}
+		},
+	)
+
+	t.Run(
+		"missing service fails on get", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			lazy := needle.InvokeLazyNamed[*Config](c, "missing")
+
+			if _, err := lazy.Get(context.Background()); err == nil {
+				t.Error("expected error for missing service")
+			}
+		},
+	)
+}
+
+func TestFactory(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"calls provider on every new", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			var calls atomic.Int32
+
+			_ = needle.Provide(
+				c, func(ctx context.Context, r needle.Resolver) (*Config, error) {
+					calls.Add(1)
+					return &Config{}, nil
+				},
+			)
+
+			factory := needle.InvokeFactory[*Config](c)
+			first := factory.MustNew(context.Background())
+			second := factory.MustNew(context.Background())
+
+			if first == second {
+				t.Error("expected distinct instances")
+			}
+			if calls.Load() != 2 {
+				t.Errorf("expected 2 provider calls, got %d", calls.Load())
+			}
+		},
+	)
+
+	t.Run(
+		"returns registered values", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			cfg := &Config{Port: 1}
+			_ = needle.ProvideNamedValue(c, "static", cfg)
+
+			got, err := needle.InvokeFactoryNamed[*Config](c, "static").New(context.Background())
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+			if got != cfg {
+				t.Error("expected registered value")
+			}
+		},
+	)
+}
+
+func TestInvokeStructDeferred(t *testing.T) {
+	t.Parallel()
+
+	c := needle.New()
+	var dbCalls atomic.Int32
+
+	_ = needle.ProvideValue(c, &Config{Port: 9090})
+	_ = needle.ProvideNamed(
+		c, "primary", func(ctx context.Context, r needle.Resolver) (*Database, error) {
+			return &Database{Name: "primary"}, nil
+		},
+	)
+	_ = needle.Provide(
+		c, func(ctx context.Context, r needle.Resolver) (*TestDatabase, error) {
+			dbCalls.Add(1)
+			return &TestDatabase{}, nil
+		},
+	)
+
+	consumer, err := needle.InvokeStruct[*LazyConsumer](c)
+	if err != nil {
+		t.Fatalf("InvokeStruct failed: %v", err)
+	}
+
+	ctx := context.Background()
+	if consumer.Config.MustGet(ctx).Port != 9090 {
+		t.Error("Config not injected lazily")
+	}
+	if consumer.Primary.MustGet(ctx).Name != "primary" {
+		t.Error("named lazy dependency not injected")
+	}
+
+	_ = consumer.Conns.MustNew(ctx)
+	_ = consumer.Conns.MustNew(ctx)
+	if dbCalls.Load() != 2 {
+		t.Errorf("expected 2 factory calls, got %d", dbCalls.Load())
+	}
+}
+
+func TestLazyBreaksCycle(t *testing.T) {
+	t.Parallel()
+
+	c := needle.New()
+
+	err := needle.ProvideFunc[*CycleA](
+		c, func(b needle.Lazy[*CycleB]) *CycleA {
+			return &CycleA{B: b}
+		},
+	)
+	if err != nil {
+		t.Fatalf("ProvideFunc for CycleA failed: %v", err)
+	}
+
+	err = needle.ProvideFunc[*CycleB](
+		c, func(a *CycleA) *CycleB {
+			return &CycleB{A: a}
+		},
+	)
+	if err != nil {
+		t.Fatalf("ProvideFunc for CycleB failed: %v", err)
+	}
+
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	a := needle.MustInvoke[*CycleA](c)
+	b, err := a.B.Get(context.Background())
+	if err != nil {
+		t.Fatalf("lazy Get failed: %v", err)
+	}
+	if b.A != a {
+		t.Error("expected cycle to resolve to the same instance")
+	}
+}
+
+func TestDeferredEdgesInGraph(t *testing.T) {
+	t.Parallel()
+
+	c := needle.New()
+
+	_ = needle.ProvideFunc[*CycleA](
+		c, func(b needle.Lazy[*CycleB]) *CycleA {
+			return &CycleA{B: b}
+		},
+	)
+	_ = needle.ProvideFunc[*CycleB](
+		c, func(a *CycleA) *CycleB {
+			return &CycleB{A: a}
+		},
+	)
+
+	var found bool
+	for _, svc := range c.Graph().Services {
+		if strings.HasSuffix(svc.Key, "CycleA") {
+			found = len(svc.Deferred) == 1 && strings.HasSuffix(svc.Deferred[0], "CycleB")
+			if len(svc.Dependencies) != 0 {
+				t.Errorf("expected no eager dependencies, got %v", svc.Dependencies)
+			}
+		}
+	}
+	if !found {
+		t.Error("expected deferred edge from CycleA to CycleB")
+	}
+
+	if !strings.Contains(c.SprintGraph(), "⇠") {
+		t.Errorf("expected deferred marker in graph output, got: %s", c.SprintGraph())
+	}
+	if !strings.Contains(c.SprintGraphDOT(), "style=dashed") {
+		t.Errorf("expected dashed edge in DOT output, got: %s", c.SprintGraphDOT())
+	}
+}
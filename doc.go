@@ -29,6 +29,82 @@
 //	needle.ProvideValue[T](c, value)         // Register an existing value
 //	needle.ProvideNamed[T](c, "name", prov)  // Register a named provider
 //
+// # Default Providers
+//
+// Libraries can ship defaults that applications override without Replace and
+// regardless of registration order:
+//
+//	needle.ProvideDefaultValue[Tracer](c, NoopTracer{})   // in the library
+//	needle.ProvideValue[Tracer](c, otelTracer)            // in the application
+//
+// A default is only used when no other provider is registered for its key.
+// Graph() records which registration won.
+//
+// # Profiles and Conditions
+//
+// Register different implementations per environment:
+//
+//	c := needle.New(needle.WithProfiles("prod", "eu"))
+//
+//	needle.ProvideValue[Mailer](c, smtpMailer, needle.WithProfile("prod"))
+//	needle.ProvideValue[Mailer](c, logMailer, needle.WithProfile("dev", "test"))
+//	needle.Provide(c, NewDebugServer, needle.WithCondition(func(c *needle.Container) bool {
+//	    return os.Getenv("DEBUG") != ""
+//	}))
+//
+// Profiles in one WithProfile are alternatives, repeated WithProfile options
+// must all match, and a "!" prefix negates a profile. Inactive providers are
+// skipped at registration and listed as inactive in Graph(). Validate checks
+// every combination declared with WithProfileCombinations, and
+// ValidateProfiles checks a single one.
+//
+// # Configuration
+//
+// ProvideConfig binds a struct from layered sources and registers it as a
+// value. Defaults come from `default` tags, then each source is applied in
+// order, so later sources win:
+//
+//	type AppConfig struct {
+//	    Port     int    `json:"port" default:"8080"`
+//	    Password string `json:"password" sensitive:"true"`
+//	    DB       struct {
+//	        URL string `json:"url" required:"true"`
+//	    } `json:"db"`
+//	}
+//
+//	needle.ProvideConfig[*AppConfig](c,
+//	    needle.FromJSON("config.json"),
+//	    needle.FromEnv("APP"),
+//	    needle.FromFlags(flag.CommandLine, os.Args[1:]),
+//	)
+//
+// Field names follow the json tag. Environment variables and flags derive
+// from the field path (APP_DB_URL, -db-url) unless set with `env` and
+// `flag` tags. Missing `required` fields and errors from a Validate() error
+// method are reported by Container.Validate. Graph shows config values with
+// `sensitive` fields redacted.
+//
+// FromFlags parses the FlagSet once and skips flags that are already
+// defined. When several configs share a FlagSet, define their flags before
+// the first config is loaded:
+//
+//	needle.DefineConfigFlags[*AppConfig](flag.CommandLine)
+//	needle.DefineConfigFlags[*WorkerConfig](flag.CommandLine)
+//
+// ReloadConfig re-reads every source. Changed configs replace the registered
+// values and every instantiated singleton that depends on them is rebuilt.
+// Replacements are built through the provider's context while other callers
+// keep resolving the previous instances, then swapped in together. Started
+// services run OnStop on the old instance and OnStart on the new one. If
+// loading, validation, a rebuild or a hook fails, the previous values and
+// instances are kept. WatchConfig reloads on file changes and SIGHUP until
+// its context is cancelled:
+//
+//	go c.WatchConfig(ctx, needle.WatchFiles("config.json"))
+//
+// WithReloadObserver reports each reloaded config with its duration and
+// error.
+//
 // # Auto-Wiring
 //
 // Reduce boilerplate with constructor auto-wiring and struct tag injection.
@@ -53,6 +129,36 @@
 //
 //	svc, err := needle.InvokeStruct[*UserService](c)
 //
+// # Generic Providers
+//
+// Register a provider once for every instantiation of a generic type. The
+// provider receives the requested type and is materialized on first use, then
+// cached like a normal singleton:
+//
+//	needle.ProvideGeneric[*Repository[any]](c, func(ctx context.Context, r needle.Resolver, t reflect.Type) (any, error) {
+//	    return newRepositoryOf(t), nil
+//	})
+//
+//	users := needle.MustInvoke[*Repository[User]](c)
+//
+// Generic providers accept WithDependencies, WithScope, WithLazy, WithRetry,
+// WithTimeout, WithProfile and WithCondition. Other options, and registering
+// one as private or namespaced inside a module, are rejected with an error.
+//
+// # Primary and Priority
+//
+// When only named providers exist for a type, unnamed resolution picks the
+// designated candidate:
+//
+//	needle.ProvideNamed(c, "redis", NewRedisCache, needle.WithPrimary())
+//	needle.ProvideNamed(c, "memory", NewMemoryCache, needle.WithPriority(10))
+//
+//	cache := needle.MustInvoke[*Cache](c) // redis
+//
+// A primary beats any priority, and priority breaks ties between primaries.
+// Validate reports several primaries or a priority tie. Services that depend
+// on the unnamed type start after the selected provider and stop before it.
+//
 // # Resolution
 //
 // Resolve dependencies using the Invoke functions:
@@ -60,6 +166,21 @@
 //	svc, err := needle.Invoke[*Service](c)   // Returns value and error
 //	svc := needle.MustInvoke[*Service](c)    // Panics on error
 //
+// # Implementations
+//
+// Find every registered service implementing an interface, without binding
+// each one explicitly. Results are ordered by service key:
+//
+//	keys := needle.ImplementationsOf[io.Closer](c)
+//	migrators, err := needle.InvokeAll[Migrator](ctx, c)
+//
+// WithInstantiatedOnly restricts the scan to singletons that already exist.
+// ImplementationsOf matches services that are not yet built by their declared
+// type, so a service registered under another interface is only reported once
+// it exists. InvokeAll resolves such services and matches the built value.
+// It returns each service once, even when it is also bound to an interface,
+// and skips pooled services because their instances must be released.
+//
 // # Optional Dependencies
 //
 // Use Optional for dependencies that may or may not be registered:
@@ -91,9 +212,42 @@
 //	)
 //
 //	c.Start(ctx)  // Starts all services in dependency order
-//	c.Stop(ctx)   // Stops all services in reverse order
+//	c.Stop(ctx)   // Stops started services in reverse start order
 //	c.Run(ctx)    // Start + wait for signal + Stop
 //
+// RunWithOptions configures the signals that trigger shutdown, a drain
+// period during which Ready reports not-ready before services stop, and a
+// callback for SIGHUP, which then must not be one of the shutdown signals. A
+// second signal cancels the graceful shutdown. ExitCode maps the result to a
+// process exit code: 0 for a clean shutdown, 128 plus the signal number when
+// shutdown was forced (130 for SIGINT, 143 for SIGTERM) and 1 for any other
+// error:
+//
+//	err := c.RunWithOptions(ctx, needle.RunOptions{
+//	    Signals:     []os.Signal{syscall.SIGINT, syscall.SIGTERM},
+//	    DrainPeriod: 5 * time.Second,
+//	    OnReload:    func(ctx context.Context) error { return c.ReloadConfig(ctx) },
+//	})
+//	os.Exit(needle.ExitCode(err))
+//
+// Restart stops and starts the container again. By default instances are
+// reused and their OnStart hooks run again. WithRestartStrategy with
+// RebuildInstances discards singleton instances instead, so providers run
+// again on the next start. Lazy services that were in use are restarted
+// with the others when reused, and rebuilt on first use otherwise. When
+// Start fails, the services and module hooks it already started are stopped
+// in reverse order and the container is left stopped, so Start or Restart
+// can be called again.
+// State reports the current lifecycle state and WithStateObserver is called
+// on every transition:
+//
+//	c := needle.New(
+//	    needle.WithRestartStrategy(needle.RebuildInstances),
+//	    needle.WithStateObserver(func(from, to needle.State) {
+//	        log.Printf("container %s -> %s", from, to)
+//	    }),
+//	)
+//
 // # Lazy Providers
 //
 // Defer instantiation until first use:
@@ -101,7 +255,72 @@
 //	needle.Provide(c, NewExpensiveService, needle.WithLazy())
 //
 // Lazy services are not instantiated during Start(). They are created on first
-// Invoke(), and their OnStart hooks run at that time if the container is running.
+// Invoke(), and their OnStart hooks run at that time if the container is
+// starting or running. Stop follows the order services actually started in,
+// so a lazy service stops before everything that started earlier. In
+// parallel mode services whose starts overlapped may stop concurrently.
+// Services that never started, such as a lazy service resolved before
+// Start, are not stopped.
+//
+// # Retries and Timeouts
+//
+// Retry providers that fail transiently, such as those dialing external
+// systems:
+//
+//	needle.Provide(c, NewDatabase,
+//	    needle.WithRetry(needle.RetryPolicy{
+//	        MaxAttempts:    5,
+//	        InitialBackoff: 100 * time.Millisecond,
+//	        Jitter:         0.2,
+//	        Retryable:      isTransient,
+//	    }),
+//	    needle.WithTimeout(2*time.Second),
+//	)
+//
+// Backoff grows by Multiplier (default 2) up to MaxBackoff. WithTimeout bounds
+// each attempt through the ctx passed to the provider and fails with a TIMEOUT
+// error. Failed attempts are reported to resolve observers, and when all
+// attempts fail the error contains a RetryError listing each one.
+//
+// # Lazy and Factory Handles
+//
+// Defer construction at the injection site instead of for the whole service.
+// Lazy[T] resolves on the first Get and caches the result; Factory[T] calls the
+// provider on every New:
+//
+//	type Handler struct {
+//	    Repo  needle.Lazy[*Repository]    `needle:""`
+//	    Conns needle.Factory[*Connection] `needle:""`
+//	}
+//
+//	repo, err := h.Repo.Get(ctx)
+//	conn, err := h.Conns.New(ctx)
+//
+// Handles can also be constructor parameters or obtained directly:
+//
+//	lazy := needle.InvokeLazy[*Repository](c)
+//	factory := needle.InvokeFactory[*Connection](c)
+//
+// Edges through Lazy and Factory are deferred: they are excluded from the
+// registration-time cycle check, which makes them useful for breaking cycles,
+// and are drawn as dashed edges in graph output.
+//
+// # Assisted Injection
+//
+// Providers can take a call-site argument in addition to container dependencies:
+//
+//	needle.ProvideWithArgs(c, func(ctx context.Context, r needle.Resolver, tenant string) (*Client, error) {
+//	    return NewClient(needle.MustInvoke[*Config](c), tenant), nil
+//	}, needle.WithArgCache(128))
+//
+//	client, err := needle.InvokeWith[string, *Client](ctx, c, "acme")
+//
+// WithArgCache keeps the most recently used instances per argument. Arguments
+// that cannot be compared, such as a slice passed as an interface, bypass the
+// cache and build a new instance. Assisted
+// providers are not started by Start and cannot be resolved with Invoke.
+// WithRetry and WithTimeout apply to each construction; lifecycle, scope and
+// alias options are rejected with an error.
 //
 // # Parallel Startup
 //
@@ -109,17 +328,97 @@
 //
 //	c := needle.New(needle.WithParallel())
 //
-// Services at the same dependency level start in parallel. Services still
-// wait for their dependencies before starting.
+// Each service starts as soon as all of its dependencies have started, so a
+// slow service only delays the services that depend on it. WithMaxConcurrency
+// caps how many hooks run at once. The first startup error cancels the
+// context of starts still in flight and nothing further is started.
+// Shutdown uses the same scheduler in reverse: a service stops once every
+// service that started after it finished starting has stopped.
+//
+//	c := needle.New(needle.WithParallel(), needle.WithMaxConcurrency(8))
+//
+// After Start, CriticalPath returns the slowest chain of services, which
+// bounds parallel boot time. FprintGraph marks it and the DOT output
+// highlights its edges. EstimateLazy predicts the saving from making a
+// service lazy, or lists the started services that would still require it:
+//
+//	path, _ := c.CriticalPath()
+//	estimate, _ := c.EstimateLazy("*main.SearchIndex")
+//
+// # Phases and Ordering
+//
+// Order startup without adding fake dependencies. Phases declared with
+// WithPhases start in order, and WithStartAfter adds a single constraint:
 //
-// # Shutdown Timeout
+//	c := needle.New(needle.WithPhases("migrate", "serve"))
 //
-// Configure a deadline for graceful shutdown:
+//	needle.Provide(c, NewMigrator, needle.WithPhase("migrate"))
+//	needle.Provide(c, NewHTTPServer, needle.WithPhase("serve"))
+//	needle.Provide(c, NewAnnouncer, needle.WithStartAfter("*main.HTTPServer"))
+//
+// Constraints apply to sequential and parallel startup, and shutdown runs in
+// reverse. Services with no ordering between them start in key order.
+// Validate reports undeclared phases, unknown keys and constraints that
+// conflict with each other or with dependencies.
+//
+// # Startup and Shutdown Timeouts
+//
+// Configure deadlines for the whole startup and graceful shutdown:
+//
+//	c := needle.New(
+//	    needle.WithStartupTimeout(time.Minute),
+//	    needle.WithShutdownTimeout(30 * time.Second),
+//	)
+//
+// Individual services can have their own budget for each hook:
+//
+//	needle.Provide(c, NewServer,
+//	    needle.WithStartTimeout(5*time.Second),
+//	    needle.WithStopTimeout(10*time.Second),
+//	)
+//
+// Hooks receive a context that expires with their budget. A hook that has not
+// returned by then fails without blocking the container, and HookTimeouts
+// reports it with its budget and whether it returned late or is still
+// running. When the shutdown deadline passes, the remaining services are
+// still stopped in order, and each of their OnStop hooks gets a short
+// fallback budget (250ms, or its stop timeout if shorter) before the next one
+// runs.
+//
+// Stop returns a *ShutdownError listing one SHUTDOWN_FAILED *Error per
+// failed service with the failing hook index and its duration. Services
+// whose hooks did not return within the fallback budget are listed in
+// Skipped. The error unwraps to every failure, so errors.Is matches
+// individual causes:
+//
+//	var shutdownErr *needle.ShutdownError
+//	if errors.As(c.Stop(ctx), &shutdownErr) {
+//	    for _, failure := range shutdownErr.Failures {
+//	        log.Printf("%s: hook %d: %v", failure.Service, failure.Hook, failure.Cause)
+//	    }
+//	}
 //
-//	c := needle.New(needle.WithShutdownTimeout(30 * time.Second))
+// # Supervised Runners
 //
-// The timeout applies to Stop() and is checked between service shutdowns.
-// Individual OnStop hooks receive the timeout context.
+// Long-running loops such as consumers and schedulers implement Runnable, or
+// register a function with WithRun. After Start, the container runs each one
+// in its own goroutine:
+//
+//	needle.Provide(c, NewConsumer,
+//	    needle.WithRestart(needle.RestartPolicy{
+//	        Mode:           needle.RestartOnFailure,
+//	        InitialBackoff: time.Second,
+//	        MaxFailures:    5,
+//	    }),
+//	)
+//
+// RestartNever (the default) runs once, RestartOnFailure restarts after an
+// error, and RestartAlways also restarts after a clean return. Restarts back
+// off exponentially up to MaxBackoff, and panics count as failures. Once
+// MaxFailures is reached the container is stopped and Run returns a
+// RUNNER_FAILED error. Health reports include each runner's state, restarts
+// and last error. Stop cancels runners and waits for them in reverse
+// dependency order before running OnStop hooks.
 //
 // # Debug Visualization
 //
@@ -130,6 +429,24 @@
 //	output := c.SprintGraph()
 //	info := c.Graph()        // Structured GraphInfo
 //
+// # Startup Plan and Trace
+//
+// Preview what Start will do without running anything:
+//
+//	plan, err := c.Plan()    // Ordered or parallel groups
+//	c.FprintPlan(os.Stdout)
+//
+// Each planned service lists its scope, phase, hook counts, timeouts and
+// whether it is skipped (lazy or assisted). In parallel mode the groups come
+// from the same ready set the scheduler uses, capped by WithMaxConcurrency,
+// and After lists the services each one waits for: it starts as soon as those
+// finish, not when its whole group does. Start and Stop record a trace
+// with begin and end times per service and a lane per goroutine in parallel
+// mode. Export it for chrome://tracing or Perfetto:
+//
+//	f, _ := os.Create("boot.json")
+//	c.Trace().WriteChromeTrace(f)
+//
 // # Modules
 //
 // Group related providers into modules:
@@ -149,6 +466,58 @@
 //	    Include(ConfigModule).
 //	    Include(HTTPModule)
 //
+// Modules mirror the top-level API with ModuleProvideFunc,
+// ModuleProvideStruct, ModuleBindNamed and ModuleDecorateNamed. Module
+// hooks run after all of the module's eager services have started and
+// before any of them stop:
+//
+//	HTTPModule.
+//	    OnStart(func(ctx context.Context) error { return warmRoutes(ctx) }).
+//	    OnStop(func(ctx context.Context) error { return drain(ctx) })
+//
+// The hooks belong to the module rather than to a service, so they do not
+// appear in Keys, Graph or Plan.
+//
+// As instantiates a module under a namespace. Every key the module
+// provides is renamed, *sql.DB becomes *sql.DB#replica and a named
+// *sql.DB#ro becomes *sql.DB#replica.ro, and references between the
+// module's own providers follow the rename. Submodules are namespaced the
+// same way, so each instance gets its own copy of them:
+//
+//	c.Apply(NewDBModule(primaryCfg), NewDBModule(replicaCfg).As("replica"))
+//	replica := needle.MustInvokeNamed[*sql.DB](c, "replica")
+//
+// A module is applied at most once per container. Including a shared
+// module from several places, or applying a module with the same name
+// again, is a no-op. Apply is all-or-nothing: if any provider fails,
+// every service registered by that call is removed and its decorators
+// are not installed.
+//
+// Modules declare what they expect from the outside with Requires,
+// which accepts other modules or keys. Validate reports unmet
+// requirements:
+//
+//	var DBModule = needle.NewModule("db").Requires(ConfigModule)
+//	needle.ModuleRequires[*slog.Logger](DBModule)
+//
+// # Module Encapsulation
+//
+// A module with an export list keeps every other provider private.
+// Private services are visible to providers in the same module and its
+// submodules, through the Resolver, ProvideFunc and ProvideStruct, but
+// not to the rest of the container. Two modules may therefore register
+// private helpers of the same type without colliding:
+//
+//	var BillingModule = needle.NewModule("billing")
+//	needle.ModuleProvide(BillingModule, NewHTTPClient)
+//	needle.ModuleProvide(BillingModule, NewInvoiceService)
+//	needle.ModuleExport[*InvoiceService](BillingModule)
+//
+// The export list applies to providers declared directly in the module.
+// Validate reports services outside the module that depend on a key the
+// module did not export. ServiceInfo.Module records the owning module,
+// and the text and DOT graphs group services by module.
+//
 // # Interface Binding
 //
 // Bind interfaces to concrete implementations:
@@ -160,6 +529,14 @@
 //
 //	needle.ModuleBind[UserRepository, *PostgresUserRepo](module)
 //
+// Expose one provider as several interfaces in the same registration:
+//
+//	needle.Provide(c, NewRedisCache, needle.As[Cache](), needle.As[io.Closer]())
+//	needle.AutoBind[*RedisCache](c, reflect.TypeFor[Cache]())
+//
+// Named bindings use the same key format as named providers, and bindings
+// appear as edges in Graph().
+//
 // # Decorators
 //
 // Wrap services with cross-cutting concerns:
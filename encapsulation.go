@@ -0,0 +1,219 @@
+package needle
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/danpasecinic/needle/internal/reflect"
+)
+
+type moduleScope struct {
+	mu        sync.RWMutex
+	path      string
+	parent    *moduleScope
+	exports   map[string]bool
+	namespace string
+	private   map[string]string
+	renamed   map[string]string
+}
+
+type moduleIndex struct {
+	mu           sync.RWMutex
+	owners       map[string]string
+	private      map[string]map[string]string
+	applied      map[*Module]bool
+	names        map[string]bool
+	requirements []moduleRequirement
+}
+
+func newModuleIndex() *moduleIndex {
+	return &moduleIndex{
+		owners:  make(map[string]string),
+		private: make(map[string]map[string]string),
+		applied: make(map[*Module]bool),
+		names:   make(map[string]bool),
+	}
+}
+
+func ModuleExport[T any](m *Module) *Module {
+	return m.Export(reflect.TypeKey[T]())
+}
+
+func ModuleExportNamed[T any](m *Module, name string) *Module {
+	return m.Export(reflect.TypeKeyNamed[T](name))
+}
+
+func (m *Module) Export(keys ...string) *Module {
+	m.exports = append(m.exports, keys...)
+	return m
+}
+
+func (m *Module) Exports() []string {
+	exports := make([]string, len(m.exports))
+	copy(exports, m.exports)
+	return exports
+}
+
+func (c *Container) enterModule(m *Module) *Container {
+	path := m.name
+	if c.scope != nil {
+		path = c.scope.path + "/" + m.name
+	}
+
+	var exports map[string]bool
+	if len(m.exports) > 0 {
+		exports = make(map[string]bool, len(m.exports))
+		for _, key := range m.exports {
+			exports[key] = true
+		}
+	}
+
+	scoped := &Container{
+		internal: c.internal,
+		config:   c.config,
+		profiles: c.profiles,
+		modules:  c.modules,
+		configs:  c.configs,
+		runners:  c.runners,
+		draining: c.draining,
+		scope: &moduleScope{
+			path:      path,
+			parent:    c.scope,
+			exports:   exports,
+			namespace: m.namespace,
+			private:   make(map[string]string),
+			renamed:   make(map[string]string),
+		},
+	}
+	scoped.resolver = &resolverAdapter{container: scoped}
+	return scoped
+}
+
+func (c *Container) localKey(key string) string {
+	s := c.scope
+	if s == nil {
+		return key
+	}
+
+	local := key
+	if s.namespace != "" {
+		key = namespaceKey(key, s.namespace)
+	}
+
+	if s.exports == nil || s.exports[local] {
+		if key != local {
+			for scope := s; scope != nil && scope.namespace == s.namespace; scope = scope.parent {
+				scope.mu.Lock()
+				scope.renamed[local] = key
+				scope.mu.Unlock()
+			}
+		}
+		return key
+	}
+
+	qualified := key + "@" + s.path
+
+	s.mu.Lock()
+	s.private[local] = qualified
+	s.mu.Unlock()
+
+	c.modules.mu.Lock()
+	if c.modules.private[local] == nil {
+		c.modules.private[local] = make(map[string]string)
+	}
+	c.modules.private[local][qualified] = s.path
+	c.modules.mu.Unlock()
+
+	return qualified
+}
+
+func (c *Container) scopeKey(key string) string {
+	if s := c.scope; s != nil && s.namespace != "" {
+		s.mu.RLock()
+		renamed, ok := s.renamed[key]
+		s.mu.RUnlock()
+		if ok {
+			return renamed
+		}
+	}
+
+	for s := c.scope; s != nil; s = s.parent {
+		s.mu.RLock()
+		qualified, ok := s.private[key]
+		s.mu.RUnlock()
+		if ok {
+			return qualified
+		}
+	}
+	return key
+}
+
+func namespaceKey(key, namespace string) string {
+	if base, name, ok := strings.Cut(key, "#"); ok {
+		return base + "#" + namespace + "." + name
+	}
+	return key + "#" + namespace
+}
+
+func (c *Container) sealModule(added []string) error {
+	for _, key := range added {
+		if err := c.internal.RewriteDependencies(key, c.scopeKey); err != nil {
+			return err
+		}
+	}
+
+	c.modules.mu.Lock()
+	defer c.modules.mu.Unlock()
+
+	for _, key := range added {
+		if _, ok := c.modules.owners[key]; !ok {
+			c.modules.owners[key] = c.scope.path
+		}
+	}
+	return nil
+}
+
+func (c *Container) moduleOf(key string) string {
+	c.modules.mu.RLock()
+	defer c.modules.mu.RUnlock()
+
+	return c.modules.owners[key]
+}
+
+func (c *Container) validateVisibility() error {
+	c.modules.mu.RLock()
+	defer c.modules.mu.RUnlock()
+
+	if len(c.modules.private) == 0 {
+		return nil
+	}
+
+	g := c.internal.Graph()
+
+	var violations []string
+	for _, key := range g.Nodes() {
+		deps := append(g.GetDependencies(key), g.GetDeferred(key)...)
+		for _, dep := range deps {
+			hidden, ok := c.modules.private[dep]
+			if !ok || c.internal.Has(dep) {
+				continue
+			}
+			modules := make([]string, 0, len(hidden))
+			for _, path := range hidden {
+				modules = append(modules, path)
+			}
+			sort.Strings(modules)
+			violations = append(
+				violations, fmt.Sprintf("%s depends on %s, which is not exported by module %s", key, dep, strings.Join(modules, ", ")),
+			)
+		}
+	}
+
+	if len(violations) > 0 {
+		sort.Strings(violations)
+		return fmt.Errorf("module visibility violations: %v", violations)
+	}
+	return nil
+}
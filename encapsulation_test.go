@@ -0,0 +1,230 @@
+package needle_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/danpasecinic/needle"
+)
+
+type BillingClient struct {
+	Owner string
+}
+
+type InvoiceService struct {
+	Client *BillingClient
+}
+
+type ShippingService struct {
+	Client *BillingClient
+}
+
+type Invoicer interface {
+	Owner() string
+}
+
+func (s *InvoiceService) Owner() string {
+	return s.Client.Owner
+}
+
+const billingClientKey = "*github.com/danpasecinic/needle_test.BillingClient"
+
+func resolveBillingClient(ctx context.Context, r needle.Resolver) (*BillingClient, error) {
+	client, err := r.Resolve(ctx, billingClientKey)
+	if err != nil {
+		return nil, err
+	}
+	return client.(*BillingClient), nil
+}
+
+func provideInvoices(m *needle.Module) {
+	needle.ModuleProvide(
+		m, func(ctx context.Context, r needle.Resolver) (*InvoiceService, error) {
+			client, err := resolveBillingClient(ctx, r)
+			if err != nil {
+				return nil, err
+			}
+			return &InvoiceService{Client: client}, nil
+		}, needle.WithDependencies(billingClientKey),
+	)
+}
+
+func billingModule(owner string) *needle.Module {
+	m := needle.NewModule("billing")
+	needle.ModuleProvideValue(m, &BillingClient{Owner: owner})
+	provideInvoices(m)
+	return needle.ModuleExport[*InvoiceService](m)
+}
+
+func TestModuleExport(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"exported services resolve with private dependencies", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			if err := c.Apply(billingModule("billing")); err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+			if err := c.Validate(); err != nil {
+				t.Fatalf("Validate failed: %v", err)
+			}
+
+			svc, err := needle.Invoke[*InvoiceService](c)
+			if err != nil {
+				t.Fatalf("Invoke failed: %v", err)
+			}
+			if svc.Client.Owner != "billing" {
+				t.Errorf("expected private client, got %q", svc.Client.Owner)
+			}
+
+			if needle.Has[*BillingClient](c) {
+				t.Error("private service should not be visible outside the module")
+			}
+			if _, err := needle.Invoke[*BillingClient](c); err == nil {
+				t.Error("expected error resolving private service")
+			}
+		},
+	)
+
+	t.Run(
+		"private services do not collide", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			shipping := needle.NewModule("shipping")
+			needle.ModuleProvideValue(shipping, &BillingClient{Owner: "shipping"})
+			needle.ModuleProvide(
+				shipping, func(ctx context.Context, r needle.Resolver) (*ShippingService, error) {
+					client, err := resolveBillingClient(ctx, r)
+					if err != nil {
+						return nil, err
+					}
+					return &ShippingService{Client: client}, nil
+				}, needle.WithDependencies(billingClientKey),
+			)
+			needle.ModuleExport[*ShippingService](shipping)
+
+			if err := c.Apply(billingModule("billing"), shipping); err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+
+			if got := needle.MustInvoke[*InvoiceService](c).Client.Owner; got != "billing" {
+				t.Errorf("expected billing client, got %q", got)
+			}
+			if got := needle.MustInvoke[*ShippingService](c).Client.Owner; got != "shipping" {
+				t.Errorf("expected shipping client, got %q", got)
+			}
+		},
+	)
+
+	t.Run(
+		"submodules see parent private services", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			invoices := needle.NewModule("invoices")
+			provideInvoices(invoices)
+
+			parent := needle.NewModule("billing").Include(invoices)
+			needle.ModuleProvideValue(parent, &BillingClient{Owner: "parent"})
+			needle.ModuleExport[*InvoiceService](parent)
+
+			if err := c.Apply(parent); err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+			if err := c.Validate(); err != nil {
+				t.Fatalf("Validate failed: %v", err)
+			}
+			if got := needle.MustInvoke[*InvoiceService](c).Client.Owner; got != "parent" {
+				t.Errorf("expected parent client, got %q", got)
+			}
+		},
+	)
+
+	t.Run(
+		"exported interface binds private implementation", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			m := needle.NewModule("billing")
+			needle.ModuleProvideValue(m, &BillingClient{Owner: "bound"})
+			provideInvoices(m)
+			needle.ModuleBind[Invoicer, *InvoiceService](m)
+			needle.ModuleExport[Invoicer](m)
+
+			if err := c.Apply(m); err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+			if err := c.Validate(); err != nil {
+				t.Fatalf("Validate failed: %v", err)
+			}
+			if got := needle.MustInvoke[Invoicer](c).Owner(); got != "bound" {
+				t.Errorf("expected bound owner, got %q", got)
+			}
+			if needle.Has[*InvoiceService](c) {
+				t.Error("implementation should stay private")
+			}
+		},
+	)
+
+	t.Run(
+		"validate rejects outside dependencies on private services", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			if err := c.Apply(billingModule("billing")); err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+			_ = needle.ProvideFunc[*ShippingService](
+				c, func(client *BillingClient) *ShippingService {
+					return &ShippingService{Client: client}
+				},
+			)
+
+			err := c.Validate()
+			if err == nil {
+				t.Fatal("expected visibility error")
+			}
+			if !strings.Contains(err.Error(), "not exported by module billing") {
+				t.Errorf("unexpected error: %v", err)
+			}
+		},
+	)
+}
+
+func TestModuleGraphGrouping(t *testing.T) {
+	t.Parallel()
+
+	c := needle.New()
+	_ = needle.ProvideValue(c, &Config{Port: 80})
+	if err := c.Apply(billingModule("billing")); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	modules := make(map[string]string)
+	for _, svc := range c.Graph().Services {
+		modules[svc.Key] = svc.Module
+	}
+	if got := modules["*github.com/danpasecinic/needle_test.InvoiceService"]; got != "billing" {
+		t.Errorf("expected billing module, got %q", got)
+	}
+	if got := modules["*github.com/danpasecinic/needle_test.Config"]; got != "" {
+		t.Errorf("expected no module for root service, got %q", got)
+	}
+
+	text := c.SprintGraph()
+	if !strings.Contains(text, "[billing]\n  ● ") {
+		t.Errorf("expected grouped text output, got: %s", text)
+	}
+	if strings.Index(text, "Config") > strings.Index(text, "[billing]") {
+		t.Errorf("expected root services before module groups, got: %s", text)
+	}
+
+	dot := c.SprintGraphDOT()
+	if !strings.Contains(dot, `subgraph "cluster_billing"`) {
+		t.Errorf("expected module cluster in DOT output, got: %s", dot)
+	}
+}
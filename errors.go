@@ -3,7 +3,9 @@ package needle
 import (
 	"errors"
 	"fmt"
+	"os"
 	"strings"
+	"time"
 )
 
 type ErrorCode uint16
@@ -26,6 +28,9 @@ const (
 	ErrCodeModuleApplyFailed
 	ErrCodeModuleInvalidProvider
 	ErrCodeDecoratorFailed
+	ErrCodeConfigFailed
+	ErrCodeRunnerFailed
+	ErrCodeShutdownForced
 )
 
 var codeNames = map[ErrorCode]string{
@@ -46,6 +51,9 @@ var codeNames = map[ErrorCode]string{
 	ErrCodeModuleApplyFailed:       "MODULE_APPLY_FAILED",
 	ErrCodeModuleInvalidProvider:   "MODULE_INVALID_PROVIDER",
 	ErrCodeDecoratorFailed:         "DECORATOR_FAILED",
+	ErrCodeConfigFailed:            "CONFIG_FAILED",
+	ErrCodeRunnerFailed:            "RUNNER_FAILED",
+	ErrCodeShutdownForced:          "SHUTDOWN_FORCED",
 }
 
 func (c ErrorCode) String() string {
@@ -56,11 +64,14 @@ func (c ErrorCode) String() string {
 }
 
 type Error struct {
-	Code    ErrorCode
-	Message string
-	Service string
-	Cause   error
-	Stack   []string
+	Code     ErrorCode
+	Message  string
+	Service  string
+	Hook     int
+	Duration time.Duration
+	Cause    error
+	Stack    []string
+	signal   os.Signal
 }
 
 func (e *Error) Error() string {
@@ -144,7 +155,7 @@ func errResolutionFailed(serviceType string, cause error) *Error {
 	).WithService(serviceType)
 }
 
-func errProviderFailed(serviceType string, cause error) *Error { //nolint:unused // reserved for future use
+func errProviderFailed(serviceType string, cause error) *Error {
 	return newError(
 		ErrCodeProviderFailed,
 		fmt.Sprintf("provider for %s returned error", serviceType),
@@ -160,7 +171,7 @@ func errStartupFailed(serviceType string, cause error) *Error { //nolint:unused
 	).WithService(serviceType)
 }
 
-func errShutdownFailed(serviceType string, cause error) *Error { //nolint:unused // reserved for future use
+func errShutdownFailed(serviceType string, cause error) *Error {
 	return newError(
 		ErrCodeShutdownFailed,
 		fmt.Sprintf("failed to stop %s", serviceType),
@@ -168,6 +179,17 @@ func errShutdownFailed(serviceType string, cause error) *Error { //nolint:unused
 	).WithService(serviceType)
 }
 
+func errStopHookFailed(serviceType string, hook int, duration time.Duration, cause error) *Error {
+	err := newError(
+		ErrCodeShutdownFailed,
+		fmt.Sprintf("OnStop hook %d for %s failed after %s", hook, serviceType, duration),
+		cause,
+	).WithService(serviceType)
+	err.Hook = hook
+	err.Duration = duration
+	return err
+}
+
 func errHealthCheckFailed(serviceType string, cause error) *Error {
 	return newError(
 		ErrCodeHealthCheckFailed,
@@ -176,42 +198,107 @@ func errHealthCheckFailed(serviceType string, cause error) *Error {
 	).WithService(serviceType)
 }
 
-func IsNotFound(err error) bool {
+func errTimeout(serviceType string, timeout time.Duration, cause error) *Error {
+	return newError(
+		ErrCodeTimeout,
+		fmt.Sprintf("provider for %s timed out after %s", serviceType, timeout),
+		cause,
+	).WithService(serviceType)
+}
+
+func errConfigFailed(serviceType string, cause error) *Error {
+	return newError(
+		ErrCodeConfigFailed,
+		fmt.Sprintf("failed to load config %s", serviceType),
+		cause,
+	).WithService(serviceType)
+}
+
+func errRunnerFailed(serviceType string, failures int, cause error) *Error {
+	return newError(
+		ErrCodeRunnerFailed,
+		fmt.Sprintf("runner for %s failed %d times", serviceType, failures),
+		cause,
+	).WithService(serviceType)
+}
+
+func errShutdownForced(sig os.Signal, cause error) *Error {
+	err := newError(
+		ErrCodeShutdownForced,
+		fmt.Sprintf("shutdown forced by %s", sig),
+		cause,
+	)
+	err.signal = sig
+	return err
+}
+
+func forcingSignal(err error) (os.Signal, bool) {
 	var e *Error
-	return errors.As(err, &e) && e.Code == ErrCodeServiceNotFound
+	if errors.As(err, &e) && e.Code == ErrCodeShutdownForced {
+		return e.signal, true
+	}
+
+	switch unwrapped := err.(type) {
+	case interface{ Unwrap() []error }:
+		for _, inner := range unwrapped.Unwrap() {
+			if sig, ok := forcingSignal(inner); ok {
+				return sig, true
+			}
+		}
+	case interface{ Unwrap() error }:
+		return forcingSignal(unwrapped.Unwrap())
+	}
+	return nil, false
+}
+
+func IsNotFound(err error) bool {
+	return hasCode(err, ErrCodeServiceNotFound)
 }
 
 func IsCircularDependency(err error) bool {
-	var e *Error
-	return errors.As(err, &e) && e.Code == ErrCodeCircularDependency
+	return hasCode(err, ErrCodeCircularDependency)
 }
 
 func IsDuplicateService(err error) bool {
-	var e *Error
-	return errors.As(err, &e) && e.Code == ErrCodeDuplicateService
+	return hasCode(err, ErrCodeDuplicateService)
 }
 
 func IsResolutionFailed(err error) bool {
-	var e *Error
-	return errors.As(err, &e) && e.Code == ErrCodeResolutionFailed
+	return hasCode(err, ErrCodeResolutionFailed)
 }
 
 func IsProviderFailed(err error) bool {
-	var e *Error
-	return errors.As(err, &e) && e.Code == ErrCodeProviderFailed
+	return hasCode(err, ErrCodeProviderFailed)
 }
 
 func IsStartupFailed(err error) bool {
-	var e *Error
-	return errors.As(err, &e) && e.Code == ErrCodeStartupFailed
+	return hasCode(err, ErrCodeStartupFailed)
 }
 
 func IsShutdownFailed(err error) bool {
-	var e *Error
-	return errors.As(err, &e) && e.Code == ErrCodeShutdownFailed
+	return hasCode(err, ErrCodeShutdownFailed)
 }
 
 func IsHealthCheckFailed(err error) bool {
-	var e *Error
-	return errors.As(err, &e) && e.Code == ErrCodeHealthCheckFailed
+	return hasCode(err, ErrCodeHealthCheckFailed)
+}
+
+func IsTimeout(err error) bool {
+	return hasCode(err, ErrCodeTimeout)
+}
+
+func IsConfigFailed(err error) bool {
+	return hasCode(err, ErrCodeConfigFailed)
+}
+
+func IsRunnerFailed(err error) bool {
+	return hasCode(err, ErrCodeRunnerFailed)
+}
+
+func IsShutdownForced(err error) bool {
+	return hasCode(err, ErrCodeShutdownForced)
+}
+
+func hasCode(err error, code ErrorCode) bool {
+	return errors.Is(err, &Error{Code: code})
 }
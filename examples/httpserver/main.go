@@ -118,8 +118,9 @@ func main() {
 	)
 
 	logger.Info("starting application")
-	if err := c.Run(context.Background()); err != nil {
+	err := c.RunWithOptions(context.Background(), needle.RunOptions{DrainPeriod: time.Second})
+	if err != nil {
 		logger.Error("application error", "error", err)
-		os.Exit(1)
 	}
+	os.Exit(needle.ExitCode(err))
 }
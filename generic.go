@@ -0,0 +1,73 @@
+package needle
+
+import (
+	"context"
+	"fmt"
+	reflectPkg "reflect"
+
+	"github.com/danpasecinic/needle/internal/container"
+	"github.com/danpasecinic/needle/internal/reflect"
+)
+
+type GenericProvider func(ctx context.Context, r Resolver, t reflectPkg.Type) (any, error)
+
+var genericUnsupported = []string{
+	"WithName", "WithOnStart", "WithOnStop", "WithPoolSize", "WithArgCache", "WithDefault", "WithPrimary",
+	"WithPriority", "As", "WithRun", "WithRestart", "WithPhase", "WithStartAfter", "WithStartTimeout",
+	"WithStopTimeout",
+}
+
+func ProvideGeneric[T any](c *Container, provider GenericProvider, opts ...ProviderOption) error {
+	cfg := &providerConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	if err := rejectOptions("ProvideGeneric", cfg, genericUnsupported...); err != nil {
+		return err
+	}
+
+	t := reflectPkg.TypeFor[T]()
+	key, ok := reflect.GenericKey(t)
+	if !ok {
+		return fmt.Errorf("%s is not a generic type instantiation", t)
+	}
+	if s := c.scope; s != nil && (s.namespace != "" || (s.exports != nil && !s.exports[key])) {
+		return fmt.Errorf("ProvideGeneric cannot register %s as private or namespaced in module %s", key, s.path)
+	}
+
+	if !c.admit(key, cfg.dependencies, cfg) {
+		return nil
+	}
+
+	resolver := c.resolver
+	wrappedProvider := func(ctx context.Context, r container.Resolver, target reflectPkg.Type) (any, error) {
+		construct := func(ctx context.Context, r container.Resolver) (any, error) {
+			return provider(ctx, resolver, target)
+		}
+		instance, err := c.withRetry(key, construct, cfg)(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		if instance == nil || !reflectPkg.TypeOf(instance).AssignableTo(target) {
+			return nil, fmt.Errorf("generic provider for %s returned %T, expected %s", key, instance, target)
+		}
+		return instance, nil
+	}
+
+	return c.internal.RegisterTemplate(
+		container.TemplateEntry{
+			Key:          key,
+			Provider:     wrappedProvider,
+			Dependencies: cfg.dependencies,
+			Scope:        cfg.scope,
+			Lazy:         cfg.lazy,
+		},
+	)
+}
+
+func MustProvideGeneric[T any](c *Container, provider GenericProvider, opts ...ProviderOption) {
+	if err := ProvideGeneric[T](c, provider, opts...); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,209 @@
+package needle_test
+
+import (
+	"context"
+	"errors"
+	reflectPkg "reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/danpasecinic/needle"
+)
+
+type Repository[T any] struct {
+	Entity string
+}
+
+type User struct{}
+
+type Order struct{}
+
+type OrderService struct {
+	Orders *Repository[Order]
+}
+
+func newRepository(ctx context.Context, r needle.Resolver, t reflectPkg.Type) (any, error) {
+	v := reflectPkg.New(t.Elem())
+	v.Elem().FieldByName("Entity").SetString(t.Elem().Name())
+	return v.Interface(), nil
+}
+
+func TestProvideGeneric(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"materializes instantiations on demand", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			var calls atomic.Int32
+
+			err := needle.ProvideGeneric[*Repository[any]](
+				c, func(ctx context.Context, r needle.Resolver, t reflectPkg.Type) (any, error) {
+					calls.Add(1)
+					return newRepository(ctx, r, t)
+				},
+			)
+			if err != nil {
+				t.Fatalf("ProvideGeneric failed: %v", err)
+			}
+
+			if !needle.Has[*Repository[User]](c) {
+				t.Error("expected Has to report generic instantiation")
+			}
+
+			users := needle.MustInvoke[*Repository[User]](c)
+			if !strings.HasPrefix(users.Entity, "Repository[") || !strings.Contains(users.Entity, "User") {
+				t.Errorf("unexpected entity: %s", users.Entity)
+			}
+
+			again := needle.MustInvoke[*Repository[User]](c)
+			if users != again {
+				t.Error("expected materialized instance to be cached as singleton")
+			}
+
+			_ = needle.MustInvoke[*Repository[Order]](c)
+			if calls.Load() != 2 {
+				t.Errorf("expected 2 provider calls, got %d", calls.Load())
+			}
+		},
+	)
+
+	t.Run(
+		"rejects non-generic types", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			err := needle.ProvideGeneric[*Config](c, newRepository)
+			if err == nil {
+				t.Error("expected error for non-generic type")
+			}
+		},
+	)
+
+	t.Run(
+		"rejects mismatched instances", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.ProvideGeneric[*Repository[any]](
+				c, func(ctx context.Context, r needle.Resolver, t reflectPkg.Type) (any, error) {
+					return &Config{}, nil
+				},
+			)
+
+			if _, err := needle.Invoke[*Repository[User]](c); err == nil {
+				t.Error("expected error for mismatched instance")
+			}
+		},
+	)
+
+	t.Run(
+		"rejects duplicate templates", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.ProvideGeneric[*Repository[any]](c, newRepository)
+			if err := needle.ProvideGeneric[*Repository[int]](c, newRepository); err == nil {
+				t.Error("expected error for duplicate template")
+			}
+		},
+	)
+
+	t.Run(
+		"rejects unsupported options", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			noop := func(ctx context.Context) error { return nil }
+			for _, opt := range []needle.ProviderOption{
+				needle.WithName("primary"), needle.WithOnStart(noop), needle.WithPrimary(), needle.As[any](),
+			} {
+				if err := needle.ProvideGeneric[*Repository[any]](c, newRepository, opt); err == nil {
+					t.Error("expected unsupported option to be rejected")
+				}
+			}
+			if len(c.Keys()) != 0 || needle.Has[*Repository[User]](c) {
+				t.Error("rejected providers should not be registered")
+			}
+		},
+	)
+
+	t.Run(
+		"applies retry and profiles", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New(needle.WithProfiles("prod"))
+			var calls atomic.Int32
+			err := needle.ProvideGeneric[*Repository[any]](
+				c, func(ctx context.Context, r needle.Resolver, t reflectPkg.Type) (any, error) {
+					if calls.Add(1) == 1 {
+						return nil, errors.New("not ready")
+					}
+					return newRepository(ctx, r, t)
+				},
+				needle.WithRetry(needle.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}),
+				needle.WithProfile("prod"),
+			)
+			if err != nil {
+				t.Fatalf("ProvideGeneric failed: %v", err)
+			}
+			if _, err := needle.Invoke[*Repository[User]](c); err != nil || calls.Load() != 2 {
+				t.Errorf("expected the retry to succeed, got %v after %d calls", err, calls.Load())
+			}
+
+			dev := needle.New(needle.WithProfiles("dev"))
+			_ = needle.ProvideGeneric[*Repository[any]](dev, newRepository, needle.WithProfile("prod"))
+			if needle.Has[*Repository[User]](dev) {
+				t.Error("expected the generic provider to be inactive outside its profile")
+			}
+		},
+	)
+}
+
+func TestProvideGenericDependencies(t *testing.T) {
+	t.Parallel()
+
+	c := needle.New()
+	_ = needle.ProvideGeneric[*Repository[any]](c, newRepository)
+
+	err := needle.ProvideFunc[*OrderService](
+		c, func(orders *Repository[Order]) *OrderService {
+			return &OrderService{Orders: orders}
+		},
+	)
+	if err != nil {
+		t.Fatalf("ProvideFunc failed: %v", err)
+	}
+
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Validate should accept template-satisfied dependencies: %v", err)
+	}
+
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer func() { _ = c.Stop(context.Background()) }()
+
+	svc := needle.MustInvoke[*OrderService](c)
+	if svc.Orders == nil {
+		t.Fatal("expected repository to be injected")
+	}
+
+	info := c.Graph()
+	if len(info.Templates) != 1 {
+		t.Errorf("expected 1 template, got %v", info.Templates)
+	}
+
+	var materialized bool
+	for _, s := range info.Services {
+		if strings.Contains(s.Key, "Repository[") && s.Template != "" {
+			materialized = s.Instantiated
+		}
+	}
+	if !materialized {
+		t.Error("expected materialized repository in graph")
+	}
+}
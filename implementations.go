@@ -0,0 +1,149 @@
+package needle
+
+import (
+	"context"
+	reflectPkg "reflect"
+	"strings"
+
+	"github.com/danpasecinic/needle/internal/container"
+	"github.com/danpasecinic/needle/internal/reflect"
+	"github.com/danpasecinic/needle/internal/scope"
+)
+
+type ScanOption func(*scanConfig)
+
+type scanConfig struct {
+	instantiatedOnly bool
+}
+
+func WithInstantiatedOnly() ScanOption {
+	return func(cfg *scanConfig) {
+		cfg.instantiatedOnly = true
+	}
+}
+
+func ImplementationsOf[I any](c *Container, opts ...ScanOption) []string {
+	cfg := &scanConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	target := reflectPkg.TypeFor[I]()
+
+	var keys []string
+	for _, entry := range c.internal.Entries() {
+		if matchesScan(entry, target, cfg) {
+			keys = append(keys, entry.Key)
+		}
+	}
+	return keys
+}
+
+func InvokeAll[I any](ctx context.Context, c *Container, opts ...ScanOption) ([]I, error) {
+	cfg := &scanConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	target := reflectPkg.TypeFor[I]()
+	hasRequestScope := container.HasRequestScope(ctx)
+
+	entries := c.internal.Entries()
+	bindings := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		if entry.Binding != "" {
+			bindings[entry.Key] = entry.Binding
+		}
+	}
+
+	var results []I
+	seen := make(map[string]bool)
+
+	for _, entry := range entries {
+		if !matchesScan(entry, target, cfg) && !mayImplement(entry, cfg) {
+			continue
+		}
+		if entry.Scope == scope.Pooled || (entry.Scope == scope.Request && !hasRequestScope) {
+			continue
+		}
+
+		resolved := bindingTarget(bindings, entry.Key)
+		if seen[resolved] {
+			continue
+		}
+		seen[resolved] = true
+
+		instance, err := c.internal.Resolve(ctx, entry.Key)
+		if err != nil {
+			return nil, errResolutionFailed(entry.Key, err)
+		}
+
+		typed, ok := instance.(I)
+		if !ok {
+			continue
+		}
+
+		results = append(results, typed)
+	}
+
+	return results, nil
+}
+
+func MustInvokeAll[I any](ctx context.Context, c *Container, opts ...ScanOption) []I {
+	v, err := InvokeAll[I](ctx, c, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func matchesScan(entry container.EntryInfo, target reflectPkg.Type, cfg *scanConfig) bool {
+	if entry.Assisted {
+		return false
+	}
+
+	singleton := entry.Scope == scope.Singleton
+	if cfg.instantiatedOnly && (!entry.Instantiated || !singleton) {
+		return false
+	}
+
+	var t reflectPkg.Type
+	if entry.Instantiated && singleton {
+		t = reflectPkg.TypeOf(entry.Instance)
+	} else {
+		t = declaredType(entry)
+	}
+
+	if t == nil {
+		return false
+	}
+	if target.Kind() == reflectPkg.Interface {
+		return t.Implements(target)
+	}
+	return t.AssignableTo(target)
+}
+
+func mayImplement(entry container.EntryInfo, cfg *scanConfig) bool {
+	if cfg.instantiatedOnly || entry.Assisted || (entry.Instantiated && entry.Scope == scope.Singleton) {
+		return false
+	}
+	t := declaredType(entry)
+	return t != nil && t.Kind() == reflectPkg.Interface
+}
+
+func bindingTarget(bindings map[string]string, key string) string {
+	for range len(bindings) {
+		next, ok := bindings[key]
+		if !ok {
+			break
+		}
+		key = next
+	}
+	return key
+}
+
+func declaredType(entry container.EntryInfo) reflectPkg.Type {
+	key, _, _ := strings.Cut(entry.Key, "#")
+	t, _ := reflect.TypeForKey(key)
+	return t
+}
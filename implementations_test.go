@@ -0,0 +1,215 @@
+package needle_test
+
+import (
+	"context"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/danpasecinic/needle"
+)
+
+type Migrator interface {
+	Migrate(ctx context.Context) error
+}
+
+type UsersMigrator struct{ closed bool }
+
+func (m *UsersMigrator) Migrate(ctx context.Context) error { return nil }
+func (m *UsersMigrator) Close() error                      { m.closed = true; return nil }
+
+type OrdersMigrator struct{}
+
+type NamedMigrator struct {
+	Name  string
+	Extra any
+}
+
+func (m NamedMigrator) Migrate(ctx context.Context) error { return nil }
+
+func (m *OrdersMigrator) Migrate(ctx context.Context) error { return nil }
+
+func TestImplementationsOf(t *testing.T) {
+	t.Parallel()
+
+	c := needle.New()
+	_ = needle.Provide(
+		c, func(ctx context.Context, r needle.Resolver) (*UsersMigrator, error) {
+			return &UsersMigrator{}, nil
+		},
+	)
+	_ = needle.ProvideValue(c, &OrdersMigrator{})
+	_ = needle.ProvideValue(c, &Config{})
+
+	keys := needle.ImplementationsOf[Migrator](c)
+	if len(keys) != 2 {
+		t.Fatalf("expected 2 migrators, got %v", keys)
+	}
+	if !slices.IsSorted(keys) {
+		t.Errorf("expected sorted keys, got %v", keys)
+	}
+
+	closers := needle.ImplementationsOf[io.Closer](c)
+	if len(closers) != 1 || !strings.HasSuffix(closers[0], "UsersMigrator") {
+		t.Errorf("expected UsersMigrator as the only closer, got %v", closers)
+	}
+
+	instantiated := needle.ImplementationsOf[Migrator](c, needle.WithInstantiatedOnly())
+	if len(instantiated) != 1 || !strings.HasSuffix(instantiated[0], "OrdersMigrator") {
+		t.Errorf("expected only the instantiated migrator, got %v", instantiated)
+	}
+}
+
+func TestInvokeAll(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"resolves matching services", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.Provide(
+				c, func(ctx context.Context, r needle.Resolver) (*UsersMigrator, error) {
+					return &UsersMigrator{}, nil
+				},
+			)
+			_ = needle.ProvideValue(c, &OrdersMigrator{})
+			_ = needle.Bind[Migrator, *OrdersMigrator](c)
+
+			migrators, err := needle.InvokeAll[Migrator](context.Background(), c)
+			if err != nil {
+				t.Fatalf("InvokeAll failed: %v", err)
+			}
+			if len(migrators) != 2 {
+				t.Fatalf("expected 2 distinct migrators, got %d", len(migrators))
+			}
+			if _, ok := migrators[0].(*OrdersMigrator); !ok {
+				t.Errorf("expected deterministic key order, got %T first", migrators[0])
+			}
+		},
+	)
+
+	t.Run(
+		"restricts to instantiated singletons", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.Provide(
+				c, func(ctx context.Context, r needle.Resolver) (*UsersMigrator, error) {
+					return &UsersMigrator{}, nil
+				},
+			)
+
+			closers := needle.MustInvokeAll[io.Closer](context.Background(), c, needle.WithInstantiatedOnly())
+			if len(closers) != 0 {
+				t.Errorf("expected no closers before instantiation, got %d", len(closers))
+			}
+
+			m := needle.MustInvoke[*UsersMigrator](c)
+			closers = needle.MustInvokeAll[io.Closer](context.Background(), c, needle.WithInstantiatedOnly())
+			if len(closers) != 1 {
+				t.Fatalf("expected 1 closer, got %d", len(closers))
+			}
+			_ = closers[0].Close()
+			if !m.closed {
+				t.Error("expected closer to be the registered instance")
+			}
+		},
+	)
+
+	t.Run(
+		"skips request scoped services without scope", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.Provide(
+				c, func(ctx context.Context, r needle.Resolver) (*UsersMigrator, error) {
+					return &UsersMigrator{}, nil
+				}, needle.WithScope(needle.Request),
+			)
+
+			migrators, err := needle.InvokeAll[Migrator](context.Background(), c)
+			if err != nil {
+				t.Fatalf("InvokeAll failed: %v", err)
+			}
+			if len(migrators) != 0 {
+				t.Errorf("expected request scoped service to be skipped, got %d", len(migrators))
+			}
+
+			ctx := needle.WithRequestScope(context.Background())
+			migrators = needle.MustInvokeAll[Migrator](ctx, c)
+			if len(migrators) != 1 {
+				t.Errorf("expected request scoped service with scope, got %d", len(migrators))
+			}
+		},
+	)
+	t.Run(
+		"matches the built value of interface services", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.Provide(
+				c, func(ctx context.Context, r needle.Resolver) (Migrator, error) {
+					return &UsersMigrator{}, nil
+				},
+			)
+
+			if keys := needle.ImplementationsOf[io.Closer](c); len(keys) != 0 {
+				t.Errorf("expected unbuilt service to match by declared type, got %v", keys)
+			}
+
+			closers, err := needle.InvokeAll[io.Closer](context.Background(), c)
+			if err != nil {
+				t.Fatalf("InvokeAll failed: %v", err)
+			}
+			if len(closers) != 1 {
+				t.Fatalf("expected the built migrator as closer, got %d", len(closers))
+			}
+
+			if keys := needle.ImplementationsOf[io.Closer](c); len(keys) != 1 {
+				t.Errorf("expected built service to match by value, got %v", keys)
+			}
+		},
+	)
+	t.Run(
+		"deduplicates by service, not by value", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.ProvideNamedValue(c, "a", NamedMigrator{Name: "same"})
+			_ = needle.ProvideNamedValue(c, "b", NamedMigrator{Name: "same"})
+			_ = needle.ProvideNamedValue(c, "c", NamedMigrator{Name: "slice", Extra: []int{1}})
+			_ = needle.ProvideValue(c, &OrdersMigrator{}, needle.As[Migrator]())
+
+			migrators, err := needle.InvokeAll[Migrator](context.Background(), c)
+			if err != nil {
+				t.Fatalf("InvokeAll failed: %v", err)
+			}
+			if len(migrators) != 4 {
+				t.Errorf("expected 3 named migrators and the bound one once, got %d", len(migrators))
+			}
+		},
+	)
+
+	t.Run(
+		"skips pooled services", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.Provide(
+				c, func(ctx context.Context, r needle.Resolver) (*UsersMigrator, error) {
+					return &UsersMigrator{}, nil
+				}, needle.WithPoolSize(1),
+			)
+
+			migrators, err := needle.InvokeAll[Migrator](context.Background(), c)
+			if err != nil {
+				t.Fatalf("InvokeAll failed: %v", err)
+			}
+			if len(migrators) != 0 {
+				t.Errorf("expected pooled services to be skipped, got %d", len(migrators))
+			}
+		},
+	)
+}
@@ -2,9 +2,12 @@ package container
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/danpasecinic/needle/internal/graph"
@@ -36,26 +39,57 @@ type Container struct {
 	decorators   map[string][]DecoratorFunc
 	decoratorsMu sync.RWMutex
 
+	templates map[string]*TemplateEntry
+
+	reloadMu  sync.Mutex
+	reloading atomic.Int32
+
+	hookTimeouts   []*HookTimeout
+	hookTimeoutsMu sync.Mutex
+
+	trace          []TraceEvent
+	startDurations map[string]time.Duration
+	traceMu        sync.Mutex
+
+	started       []startRecord
+	sequenceClock uint64
+	sequenceMu    sync.Mutex
+
+	moduleHooks    []*moduleHooks
+	moduleStopErrs []error
+	moduleHooksMu  sync.Mutex
+
 	onResolve []ResolveHook
 	onProvide []ProvideHook
 	onStart   []StartHook
 	onStop    []StopHook
+	onState   []StateHook
 
-	parallel bool
+	parallel         bool
+	maxConcurrency   int
+	rebuildOnRestart bool
+	shutdownTimeout  time.Duration
+	resume           map[string]bool
 }
 
 type ResolveHook func(key string, duration time.Duration, err error)
 type ProvideHook func(key string)
 type StartHook func(key string, duration time.Duration, err error)
 type StopHook func(key string, duration time.Duration, err error)
+type StateHook func(from, to State)
 
 type Config struct {
-	Logger    *slog.Logger
-	OnResolve []ResolveHook
-	OnProvide []ProvideHook
-	OnStart   []StartHook
-	OnStop    []StopHook
-	Parallel  bool
+	Logger           *slog.Logger
+	OnResolve        []ResolveHook
+	OnProvide        []ProvideHook
+	OnStart          []StartHook
+	OnStop           []StopHook
+	OnState          []StateHook
+	Parallel         bool
+	MaxConcurrency   int
+	RebuildOnRestart bool
+	ShutdownTimeout  time.Duration
+	Phases           []string
 }
 
 func New(cfg *Config) *Container {
@@ -64,40 +98,115 @@ func New(cfg *Config) *Container {
 		logger = slog.Default()
 	}
 
+	g := graph.New()
+	if len(cfg.Phases) > 0 {
+		g.SetPhaseOrder(cfg.Phases)
+	}
+
 	return &Container{
-		registry:   NewRegistry(),
-		graph:      graph.New(),
-		logger:     logger,
-		resolving:  make(map[string]bool),
-		decorators: make(map[string][]DecoratorFunc),
-		onResolve:  cfg.OnResolve,
-		onProvide:  cfg.OnProvide,
-		onStart:    cfg.OnStart,
-		onStop:     cfg.OnStop,
-		parallel:   cfg.Parallel,
+		registry:         NewRegistry(),
+		graph:            g,
+		logger:           logger,
+		resolving:        make(map[string]bool),
+		decorators:       make(map[string][]DecoratorFunc),
+		templates:        make(map[string]*TemplateEntry),
+		onResolve:        cfg.OnResolve,
+		onProvide:        cfg.OnProvide,
+		onStart:          cfg.OnStart,
+		onStop:           cfg.OnStop,
+		onState:          cfg.OnState,
+		parallel:         cfg.Parallel,
+		maxConcurrency:   cfg.MaxConcurrency,
+		rebuildOnRestart: cfg.RebuildOnRestart,
+		shutdownTimeout:  cfg.ShutdownTimeout,
 	}
 }
 
+var ErrDefaultSkipped = errors.New("default provider skipped: service already registered")
+
 func (c *Container) Register(key string, provider ProviderFunc, dependencies []string) error {
+	return c.register(
+		key, dependencies, false, func() {
+			c.registry.RegisterUnsafe(key, provider, dependencies)
+		},
+	)
+}
+
+func (c *Container) RegisterDefault(key string, provider ProviderFunc, dependencies []string) error {
+	return c.register(
+		key, dependencies, true, func() {
+			c.registry.RegisterUnsafe(key, provider, dependencies)
+		},
+	)
+}
+
+func (c *Container) RegisterWithArgs(key string, provider ArgProviderFunc, dependencies []string) error {
+	return c.register(
+		key, dependencies, false, func() {
+			c.registry.RegisterArgsUnsafe(key, provider, dependencies)
+		},
+	)
+}
+
+func (c *Container) RegisterValue(key string, value any) error {
+	return c.register(
+		key, nil, false, func() {
+			c.registry.RegisterValueUnsafe(key, value)
+		},
+	)
+}
+
+func (c *Container) RegisterValueDefault(key string, value any) error {
+	return c.register(
+		key, nil, true, func() {
+			c.registry.RegisterValueUnsafe(key, value)
+		},
+	)
+}
+
+func (c *Container) register(key string, dependencies []string, isDefault bool, add func()) error {
 	c.mu.Lock()
 
-	if c.registry.HasUnsafe(key) {
-		c.mu.Unlock()
-		return fmt.Errorf("service already registered: %s", key)
+	previous, exists := c.registry.GetUnsafe(key)
+	if exists {
+		switch {
+		case isDefault && !previous.Default:
+			c.registry.SetOriginUnsafe(key, false, true)
+			c.mu.Unlock()
+			c.logger.Debug("default provider overridden", "service", key)
+			return ErrDefaultSkipped
+		case isDefault || !previous.Default:
+			c.mu.Unlock()
+			return fmt.Errorf("service already registered: %s", key)
+		}
 	}
 
-	c.registry.RegisterUnsafe(key, provider, dependencies)
+	previousDeferred := c.graph.GetDeferred(key)
+
+	add()
+	c.registry.SetOriginUnsafe(key, isDefault, exists)
 	c.graph.AddNodeUnsafe(key, dependencies)
+	c.graph.SetDeferredUnsafe(key, nil)
 
 	if len(dependencies) > 0 && c.graph.HasCycle() {
-		c.registry.RemoveUnsafe(key)
-		c.graph.RemoveNodeUnsafe(key)
+		if exists {
+			c.registry.RestoreUnsafe(previous)
+			c.graph.AddNodeUnsafe(key, previous.Dependencies)
+			c.graph.SetDeferredUnsafe(key, previousDeferred)
+		} else {
+			c.registry.RemoveUnsafe(key)
+			c.graph.RemoveNodeUnsafe(key)
+		}
 		c.mu.Unlock()
 		return fmt.Errorf("circular dependency detected for: %s", key)
 	}
 
 	c.mu.Unlock()
 
+	if exists {
+		c.logger.Debug("default provider overridden", "service", key)
+	}
+
 	for _, hook := range c.onProvide {
 		hook(key)
 	}
@@ -105,31 +214,128 @@ func (c *Container) Register(key string, provider ProviderFunc, dependencies []s
 	return nil
 }
 
-func (c *Container) RegisterValue(key string, value any) error {
+func (c *Container) Unregister(key string) {
 	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	if c.registry.HasUnsafe(key) {
-		c.mu.Unlock()
-		return fmt.Errorf("service already registered: %s", key)
+	c.registry.RemoveUnsafe(key)
+	c.graph.RemoveNodeUnsafe(key)
+}
+
+type Checkpoint struct {
+	entry    *ServiceEntry
+	deferred []string
+	after    []string
+	phase    string
+}
+
+func (cp Checkpoint) Key() string {
+	return cp.entry.Key
+}
+
+func (c *Container) Checkpoint(key string) (Checkpoint, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.registry.GetUnsafe(key)
+	if !ok {
+		return Checkpoint{}, false
 	}
+	return Checkpoint{
+		entry:    entry,
+		deferred: c.graph.GetDeferred(key),
+		after:    c.graph.GetStartAfter(key),
+		phase:    c.graph.GetPhase(key),
+	}, true
+}
 
-	c.registry.RegisterValueUnsafe(key, value)
-	c.graph.AddNodeUnsafe(key, nil)
+func (c *Container) Restore(cp Checkpoint) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	c.mu.Unlock()
+	key := cp.entry.Key
+	if current, ok := c.registry.GetUnsafe(key); ok && current == cp.entry {
+		return false
+	}
 
-	for _, hook := range c.onProvide {
-		hook(key)
+	c.registry.RestoreUnsafe(cp.entry)
+	c.graph.AddNodeUnsafe(key, cp.entry.Dependencies)
+	c.graph.SetDeferredUnsafe(key, cp.deferred)
+	c.graph.SetStartAfter(key, cp.after)
+	c.graph.SetPhase(key, cp.phase)
+	return true
+}
+
+func (c *Container) SetDeferred(key string, dependencies []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.registry.HasUnsafe(key) {
+		return
+	}
+	c.graph.SetDeferredUnsafe(key, dependencies)
+}
+
+func (c *Container) RewriteDependencies(key string, rewrite func(string) string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.registry.GetUnsafe(key)
+	if !ok {
+		return nil
+	}
+
+	previous := entry.Dependencies
+	previousDeferred := c.graph.GetDeferred(key)
+
+	dependencies := rewriteKeys(previous, rewrite)
+	deferred := rewriteKeys(previousDeferred, rewrite)
+	binding := entry.Binding
+	if binding != "" {
+		binding = rewrite(binding)
+	}
+
+	if slices.Equal(dependencies, previous) && slices.Equal(deferred, previousDeferred) && binding == entry.Binding {
+		return nil
+	}
+
+	entry.Dependencies = dependencies
+	c.graph.AddNodeUnsafe(key, dependencies)
+	c.graph.SetDeferredUnsafe(key, deferred)
+
+	if len(dependencies) > 0 && c.graph.HasCycle() {
+		entry.Dependencies = previous
+		c.graph.AddNodeUnsafe(key, previous)
+		c.graph.SetDeferredUnsafe(key, previousDeferred)
+		return fmt.Errorf("circular dependency detected for: %s", key)
 	}
 
+	entry.Binding = binding
 	return nil
 }
 
+func rewriteKeys(keys []string, rewrite func(string) string) []string {
+	if len(keys) == 0 {
+		return keys
+	}
+	result := make([]string, len(keys))
+	for i, key := range keys {
+		result[i] = rewrite(key)
+	}
+	return result
+}
+
 func (c *Container) Has(key string) bool {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	return c.registry.Has(key)
+	if c.registry.Has(key) {
+		return true
+	}
+	if _, _, ok := c.matchTemplateUnsafe(key); ok {
+		return true
+	}
+	return c.selectUnsafe(key).winner != ""
 }
 
 func (c *Container) Keys() []string {
@@ -139,6 +345,13 @@ func (c *Container) Keys() []string {
 	return c.registry.Keys()
 }
 
+func (c *Container) Entries() []EntryInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.registry.Snapshot()
+}
+
 func (c *Container) GetInstance(key string) (any, bool) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -157,7 +370,16 @@ func (c *Container) Validate() error {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	missing := c.graph.Validate()
+	var missing []string
+	for _, key := range c.graph.Validate() {
+		if _, _, ok := c.matchTemplateUnsafe(key); ok {
+			continue
+		}
+		if sel := c.selectUnsafe(key); sel.winner != "" || len(sel.conflicts) > 0 {
+			continue
+		}
+		missing = append(missing, key)
+	}
 	if len(missing) > 0 {
 		return fmt.Errorf("missing dependencies: %v", missing)
 	}
@@ -167,6 +389,20 @@ func (c *Container) Validate() error {
 		return fmt.Errorf("circular dependencies detected: %v", cycles)
 	}
 
+	if phases := c.graph.UndeclaredPhases(); len(phases) > 0 {
+		return fmt.Errorf("undeclared lifecycle phases: %v", phases)
+	}
+	if missing := c.graph.MissingStartAfter(); len(missing) > 0 {
+		return fmt.Errorf("start-after constraints on unknown services: %v", missing)
+	}
+	if cycle := c.graph.FindOrderingCycle(); cycle != nil {
+		return fmt.Errorf("conflicting startup ordering constraints: %v", cycle)
+	}
+
+	if conflicts := c.selectionConflictsUnsafe(); len(conflicts) > 0 {
+		return fmt.Errorf("ambiguous provider selection: %v", conflicts)
+	}
+
 	return nil
 }
 
@@ -174,6 +410,7 @@ func (c *Container) Graph() *graph.Graph {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
+	c.graph.SetSelected(c.selectionsUnsafe())
 	return c.graph.Clone()
 }
 
@@ -203,6 +440,38 @@ func (c *Container) SetPoolSize(key string, size int) {
 	c.registry.SetPoolSize(key, size)
 }
 
+func (c *Container) SetBinding(key string, implKey string) {
+	c.registry.SetBinding(key, implKey)
+}
+
+func (c *Container) Binding(key string) (string, bool) {
+	entry, exists := c.registry.GetEntry(key)
+	if !exists || entry.Binding == "" {
+		return "", false
+	}
+	return entry.Binding, true
+}
+
+func (c *Container) SetArgCacheSize(key string, size int) {
+	c.registry.SetArgCacheSize(key, size)
+}
+
 func (c *Container) SetLazy(key string, lazy bool) {
 	c.registry.SetLazy(key, lazy)
 }
+
+func (c *Container) SetPhase(key, phase string) {
+	c.graph.SetPhase(key, phase)
+}
+
+func (c *Container) SetStartAfter(key string, after []string) {
+	c.graph.SetStartAfter(key, after)
+}
+
+func (c *Container) SetStartTimeout(key string, timeout time.Duration) {
+	c.registry.SetStartTimeout(key, timeout)
+}
+
+func (c *Container) SetStopTimeout(key string, timeout time.Duration) {
+	c.registry.SetStopTimeout(key, timeout)
+}
@@ -3,6 +3,9 @@ package container
 import (
 	"context"
 	"errors"
+	"fmt"
+	"maps"
+	"slices"
 	"testing"
 )
 
@@ -325,3 +328,96 @@ func BenchmarkContainer_Register(b *testing.B) {
 		_ = c.RegisterValue("test", "value")
 	}
 }
+
+func TestContainer_ResolveWithArg(t *testing.T) {
+	t.Parallel()
+
+	c := New(&Config{})
+
+	err := c.RegisterWithArgs(
+		"client", func(ctx context.Context, r Resolver, arg any) (any, error) {
+			return "client-" + arg.(string), nil
+		}, nil,
+	)
+	if err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	c.SetArgCacheSize("client", 1)
+
+	ctx := context.Background()
+	instance, err := c.ResolveWithArg(ctx, "client", "a")
+	if err != nil {
+		t.Fatalf("failed to resolve: %v", err)
+	}
+	if instance != "client-a" {
+		t.Errorf("expected client-a, got %v", instance)
+	}
+
+	_, _ = c.ResolveWithArg(ctx, "client", "b")
+	entry, _ := c.registry.GetEntry("client")
+	if entry.argCache.Len() != 1 {
+		t.Errorf("expected cache bounded to 1 entry, got %d", entry.argCache.Len())
+	}
+
+	if _, err := c.Resolve(ctx, "client"); err == nil {
+		t.Error("expected error resolving assisted provider without argument")
+	}
+}
+
+func TestContainer_ShutdownSchedule(t *testing.T) {
+	t.Parallel()
+
+	c := New(&Config{})
+
+	// Two waves of overlapping starts: every service of the second wave began
+	// after every service of the first one ended.
+	const wave = 100
+	for _, prefix := range []string{"first", "second"} {
+		begins := make([]uint64, wave)
+		keys := make([]string, wave)
+		for i := range wave {
+			keys[i] = fmt.Sprintf("%s-%d", prefix, i)
+			begins[i] = c.beginStart()
+		}
+		for i := range wave {
+			c.recordStarted(keys[i], begins[i])
+		}
+	}
+
+	schedule := c.shutdownSchedule()
+
+	edges := 0
+	for _, next := range schedule.Next {
+		edges += len(next)
+	}
+	if edges > 4*wave {
+		t.Errorf("expected a linear number of edges, got %d", edges)
+	}
+
+	pending := maps.Clone(schedule.Pending)
+	ready := slices.Clone(schedule.Roots)
+	stopped := make(map[string]int)
+	for len(ready) > 0 {
+		key := ready[0]
+		ready = ready[1:]
+		stopped[key] = len(stopped)
+		for _, next := range schedule.Next[key] {
+			pending[next]--
+			if pending[next] == 0 {
+				ready = append(ready, next)
+			}
+		}
+	}
+
+	records := c.startRecords()
+	for _, earlier := range records {
+		if _, ok := stopped[earlier.key]; !ok {
+			t.Fatalf("%s was never stopped", earlier.key)
+		}
+		for _, later := range records {
+			if later.begin > earlier.end && stopped[later.key] > stopped[earlier.key] {
+				t.Errorf("%s must stop before %s", later.key, earlier.key)
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	reflectPkg "reflect"
+
+	"github.com/danpasecinic/needle/internal/reflect"
+	"github.com/danpasecinic/needle/internal/scope"
+)
+
+type TemplateProviderFunc func(ctx context.Context, r Resolver, t reflectPkg.Type) (any, error)
+
+type TemplateEntry struct {
+	Key          string
+	Provider     TemplateProviderFunc
+	Dependencies []string
+	Scope        scope.Scope
+	Lazy         bool
+}
+
+func (c *Container) RegisterTemplate(entry TemplateEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.templates[entry.Key]; exists {
+		return fmt.Errorf("generic provider already registered: %s", entry.Key)
+	}
+
+	c.templates[entry.Key] = &entry
+	return nil
+}
+
+func (c *Container) Templates() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	keys := make([]string, 0, len(c.templates))
+	for key := range c.templates {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (c *Container) matchTemplateUnsafe(key string) (*TemplateEntry, reflectPkg.Type, bool) {
+	if len(c.templates) == 0 {
+		return nil, nil, false
+	}
+
+	t, ok := reflect.TypeForKey(key)
+	if !ok {
+		return nil, nil, false
+	}
+
+	templateKey, ok := reflect.GenericKey(t)
+	if !ok {
+		return nil, nil, false
+	}
+
+	tmpl, ok := c.templates[templateKey]
+	return tmpl, t, ok
+}
+
+func (c *Container) materialize(key string) bool {
+	c.mu.RLock()
+	tmpl, t, ok := c.matchTemplateUnsafe(key)
+	c.mu.RUnlock()
+
+	if !ok {
+		return false
+	}
+
+	provider := func(ctx context.Context, r Resolver) (any, error) {
+		return tmpl.Provider(ctx, r, t)
+	}
+
+	if err := c.Register(key, provider, tmpl.Dependencies); err != nil {
+		return c.Has(key)
+	}
+
+	c.registry.SetTemplate(key, tmpl.Key)
+	if tmpl.Scope != scope.Singleton {
+		c.registry.SetScope(key, tmpl.Scope)
+	}
+	if tmpl.Lazy {
+		c.registry.SetLazy(key, true)
+	}
+
+	c.logger.Debug("materialized generic provider", "service", key, "template", tmpl.Key)
+	return true
+}
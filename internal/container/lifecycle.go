@@ -2,38 +2,85 @@ package container
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"sync"
 	"time"
 )
 
 func (c *Container) Start(ctx context.Context) error {
 	c.mu.Lock()
-	if c.state != StateNew && c.state != StateStopped {
+	from := c.state
+	if from != StateNew && from != StateStopped {
 		c.mu.Unlock()
 		return fmt.Errorf("container already started")
 	}
 	c.state = StateStarting
+	c.resume = nil
+	if from == StateStopped {
+		c.resume = make(map[string]bool)
+		for _, key := range c.registry.PrepareRestart(c.rebuildOnRestart) {
+			c.resume[key] = true
+		}
+	}
 	c.mu.Unlock()
-
-	var err error
-	if c.parallel {
-		err = c.startParallel(ctx)
-	} else {
-		err = c.startSequential(ctx)
+	c.callStateHooks(from, StateStarting)
+
+	c.hookTimeoutsMu.Lock()
+	c.hookTimeouts = nil
+	c.hookTimeoutsMu.Unlock()
+
+	c.traceMu.Lock()
+	c.trace = nil
+	c.startDurations = make(map[string]time.Duration)
+	c.traceMu.Unlock()
+	c.resetSequence()
+	c.syncSelections()
+
+	err := c.prepareModuleHooks(ctx)
+	if err == nil {
+		if c.parallel {
+			err = c.startParallel(ctx)
+		} else {
+			err = c.startSequential(ctx)
+		}
 	}
 
 	if err != nil {
-		return err
+		return c.abortStart(ctx, err)
 	}
 
 	c.mu.Lock()
 	c.state = StateRunning
 	c.mu.Unlock()
+	c.callStateHooks(StateStarting, StateRunning)
 
 	return nil
 }
 
+func (c *Container) abortStart(ctx context.Context, cause error) error {
+	c.mu.Lock()
+	c.state = StateStopping
+	c.mu.Unlock()
+	c.callStateHooks(StateStarting, StateStopping)
+
+	stopCtx, cancel := context.WithoutCancel(ctx), context.CancelFunc(func() {})
+	if c.shutdownTimeout > 0 {
+		stopCtx, cancel = context.WithTimeout(stopCtx, c.shutdownTimeout)
+	}
+	defer cancel()
+	errs := c.stopStarted(stopCtx)
+
+	c.mu.Lock()
+	c.state = StateStopped
+	c.mu.Unlock()
+	c.callStateHooks(StateStopping, StateStopped)
+
+	if len(errs) > 0 {
+		return errors.Join(cause, newShutdownError(errs))
+	}
+	return cause
+}
+
 func (c *Container) startSequential(ctx context.Context) error {
 	order, err := c.graph.StartupOrder()
 	if err != nil {
@@ -41,7 +88,7 @@ func (c *Container) startSequential(ctx context.Context) error {
 	}
 
 	for _, key := range order {
-		if err := c.startService(ctx, key); err != nil {
+		if err := c.startService(ctx, key, 0); err != nil {
 			return err
 		}
 	}
@@ -50,56 +97,20 @@ func (c *Container) startSequential(ctx context.Context) error {
 }
 
 func (c *Container) startParallel(ctx context.Context) error {
-	groups, err := c.graph.ParallelStartupGroups()
+	schedule, err := c.graph.StartupSchedule()
 	if err != nil {
-		return fmt.Errorf("failed to determine startup groups: %w", err)
-	}
-
-	for _, group := range groups {
-		if err := c.startGroup(ctx, group.Nodes); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-func (c *Container) startGroup(ctx context.Context, keys []string) error {
-	if len(keys) == 1 {
-		return c.startService(ctx, keys[0])
-	}
-
-	var wg sync.WaitGroup
-	errCh := make(chan error, len(keys))
-
-	for _, key := range keys {
-		if c.registry.IsLazy(key) {
-			continue
-		}
-
-		wg.Add(1)
-		go func(k string) {
-			defer wg.Done()
-			if err := c.startService(ctx, k); err != nil {
-				errCh <- err
-			}
-		}(key)
+		return fmt.Errorf("failed to determine startup schedule: %w", err)
 	}
 
-	wg.Wait()
-	close(errCh)
-
-	for err := range errCh {
-		if err != nil {
-			return err
-		}
+	if errs := c.runSchedule(ctx, schedule, c.skipStart, c.startService, true); len(errs) > 0 {
+		return errs[0]
 	}
 
 	return nil
 }
 
-func (c *Container) startService(ctx context.Context, key string) error {
-	if c.registry.IsLazy(key) {
+func (c *Container) startService(ctx context.Context, key string, lane int) error {
+	if c.skipStart(key) {
 		return nil
 	}
 
@@ -107,6 +118,7 @@ func (c *Container) startService(ctx context.Context, key string) error {
 
 	if _, err := c.Resolve(ctx, key); err != nil {
 		c.callStartHooks(key, time.Since(start), err)
+		c.recordTrace(key, "start", lane, start, err)
 		return fmt.Errorf("failed to resolve %s during startup: %w", key, err)
 	}
 
@@ -115,20 +127,34 @@ func (c *Container) startService(ctx context.Context, key string) error {
 		return nil
 	}
 
+	begin := c.beginStart()
 	var startErr error
-	for _, hook := range entry.OnStart {
+	for i, hook := range entry.OnStart {
 		c.logger.Debug("running OnStart hook", "service", key)
-		if err := hook(ctx); err != nil {
+		if err := c.runHook(ctx, key, "OnStart", i, hook, entry.StartTimeout); err != nil {
 			startErr = fmt.Errorf("OnStart hook failed for %s: %w", key, err)
 			break
 		}
 	}
+	if startErr == nil {
+		c.recordStarted(key, begin)
+		startErr = c.memberStarted(ctx, key)
+	}
 
 	c.registry.SetStartRan(key)
 	c.callStartHooks(key, time.Since(start), startErr)
+	c.recordTrace(key, "start", lane, start, startErr)
+	c.recordStartDuration(key, time.Since(start))
 	return startErr
 }
 
+func (c *Container) skipStart(key string) bool {
+	if c.registry.IsAssisted(key) {
+		return true
+	}
+	return c.registry.IsLazy(key) && !c.resume[key]
+}
+
 func (c *Container) callStartHooks(key string, duration time.Duration, err error) {
 	for _, hook := range c.onStart {
 		hook(key, duration, err)
@@ -143,37 +169,40 @@ func (c *Container) Stop(ctx context.Context) error {
 	}
 	c.state = StateStopping
 	c.mu.Unlock()
+	c.callStateHooks(StateRunning, StateStopping)
 
-	var errs []error
-	if c.parallel {
-		errs = c.stopParallel(ctx)
-	} else {
-		errs = c.stopSequential(ctx)
-	}
+	errs := c.stopStarted(ctx)
 
 	c.mu.Lock()
 	c.state = StateStopped
 	c.mu.Unlock()
+	c.callStateHooks(StateStopping, StateStopped)
 
 	if len(errs) > 0 {
-		return fmt.Errorf("shutdown errors: %v", errs)
+		return newShutdownError(errs)
 	}
 	return nil
 }
 
-func (c *Container) stopSequential(ctx context.Context) []error {
-	order, err := c.graph.ShutdownOrder()
-	if err != nil {
-		return []error{fmt.Errorf("failed to determine shutdown order: %w", err)}
+func (c *Container) stopStarted(ctx context.Context) []error {
+	var errs []error
+	if c.parallel {
+		errs = c.stopParallel(ctx)
+	} else {
+		errs = c.stopSequential(ctx)
 	}
+	return append(errs, c.stopRemainingModules(ctx)...)
+}
 
+func (c *Container) stopSequential(ctx context.Context) []error {
 	var errs []error
-	for _, key := range order {
-		if err := ctx.Err(); err != nil {
+	expired := false
+	for _, key := range c.StopSequence() {
+		if err := ctx.Err(); err != nil && !expired {
 			errs = append(errs, fmt.Errorf("shutdown timeout exceeded: %w", err))
-			break
+			expired = true
 		}
-		if stopErr := c.stopService(ctx, key); stopErr != nil {
+		if stopErr := c.stopService(ctx, key, 0); stopErr != nil {
 			errs = append(errs, stopErr)
 		}
 	}
@@ -182,77 +211,54 @@ func (c *Container) stopSequential(ctx context.Context) []error {
 }
 
 func (c *Container) stopParallel(ctx context.Context) []error {
-	groups, err := c.graph.ParallelShutdownGroups()
-	if err != nil {
-		return []error{fmt.Errorf("failed to determine shutdown groups: %w", err)}
-	}
-
-	var allErrs []error
-	for _, group := range groups {
-		if err := ctx.Err(); err != nil {
-			allErrs = append(allErrs, fmt.Errorf("shutdown timeout exceeded: %w", err))
-			break
-		}
-		errs := c.stopGroup(ctx, group.Nodes)
-		allErrs = append(allErrs, errs...)
-	}
-
-	return allErrs
-}
-
-func (c *Container) stopGroup(ctx context.Context, keys []string) []error {
-	if len(keys) == 1 {
-		if err := c.stopService(ctx, keys[0]); err != nil {
-			return []error{err}
-		}
-		return nil
-	}
-
-	var mu sync.Mutex
-	var errs []error
-	var wg sync.WaitGroup
-
-	for _, key := range keys {
+	schedule := c.shutdownSchedule()
+	skip := func(key string) bool {
 		entry, exists := c.registry.GetEntry(key)
-		if !exists || !entry.Instantiated {
-			continue
-		}
-
-		wg.Add(1)
-		go func(k string) {
-			defer wg.Done()
-			if err := c.stopService(ctx, k); err != nil {
-				mu.Lock()
-				errs = append(errs, err)
-				mu.Unlock()
-			}
-		}(key)
+		return !exists || !entry.Instantiated
 	}
-
-	wg.Wait()
-	return errs
+	return c.runSchedule(ctx, schedule, skip, c.stopService, false)
 }
 
-func (c *Container) stopService(ctx context.Context, key string) error {
+func (c *Container) stopService(ctx context.Context, key string, lane int) error {
 	entry, exists := c.registry.GetEntry(key)
 	if !exists || !entry.Instantiated {
 		return nil
 	}
+	c.memberStopping(ctx, key)
 
 	start := time.Now()
-	var stopErr error
+	var failure *StopFailure
+	var hookErrs []error
 
 	for i := len(entry.OnStop) - 1; i >= 0; i-- {
 		c.logger.Debug("running OnStop hook", "service", key)
-		if err := entry.OnStop[i](ctx); err != nil {
-			stopErr = fmt.Errorf("OnStop hook failed for %s: %w", key, err)
+		hookStart := time.Now()
+		if skipped, err := c.runStopHook(ctx, key, i, entry.OnStop[i], entry.StopTimeout); err != nil {
+			if failure == nil {
+				failure = &StopFailure{Key: key, Hook: i, Duration: time.Since(hookStart), Skipped: true}
+			}
+			failure.Skipped = failure.Skipped && skipped
+			hookErrs = append(hookErrs, err)
 		}
 	}
 
+	var stopErr error
+	if failure != nil {
+		failure.Err = errors.Join(hookErrs...)
+		stopErr = failure
+	}
+
 	c.callStopHooks(key, time.Since(start), stopErr)
+	c.recordTrace(key, "stop", lane, start, stopErr)
 	return stopErr
 }
 
+func (c *Container) callStateHooks(from, to State) {
+	for _, hook := range c.onState {
+		hook(from, to)
+	}
+}
+
 func (c *Container) callStopHooks(key string, duration time.Duration, err error) {
 	for _, hook := range c.onStop {
 		hook(key, duration, err)
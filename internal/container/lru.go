@@ -0,0 +1,68 @@
+package container
+
+import (
+	"container/list"
+	"reflect"
+	"sync"
+)
+
+type argCache struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List
+	items map[any]*list.Element
+}
+
+type argCacheItem struct {
+	arg      any
+	instance any
+}
+
+func hashable(arg any) bool {
+	return arg == nil || reflect.ValueOf(arg).Comparable()
+}
+
+func newArgCache(size int) *argCache {
+	return &argCache{
+		size:  size,
+		order: list.New(),
+		items: make(map[any]*list.Element, size),
+	}
+}
+
+func (a *argCache) Get(arg any) (any, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	elem, ok := a.items[arg]
+	if !ok {
+		return nil, false
+	}
+	a.order.MoveToFront(elem)
+	return elem.Value.(*argCacheItem).instance, true
+}
+
+func (a *argCache) Put(arg any, instance any) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if elem, ok := a.items[arg]; ok {
+		elem.Value.(*argCacheItem).instance = instance
+		a.order.MoveToFront(elem)
+		return
+	}
+
+	a.items[arg] = a.order.PushFront(&argCacheItem{arg: arg, instance: instance})
+
+	for a.order.Len() > a.size {
+		oldest := a.order.Back()
+		a.order.Remove(oldest)
+		delete(a.items, oldest.Value.(*argCacheItem).arg)
+	}
+}
+
+func (a *argCache) Len() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.order.Len()
+}
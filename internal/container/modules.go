@@ -0,0 +1,186 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+type moduleHooks struct {
+	path    string
+	members []string
+	onStart []Hook
+	onStop  []Hook
+	pending int
+	started bool
+	stop    *sync.Once
+}
+
+func (c *Container) AddModuleHooks(path string, members []string, onStart, onStop []Hook) error {
+	c.moduleHooksMu.Lock()
+	defer c.moduleHooksMu.Unlock()
+
+	for _, m := range c.moduleHooks {
+		if m.path == path {
+			return fmt.Errorf("module hooks already registered: %s", path)
+		}
+	}
+	c.moduleHooks = append(
+		c.moduleHooks, &moduleHooks{
+			path:    path,
+			members: slices.Clone(members),
+			onStart: slices.Clone(onStart),
+			onStop:  slices.Clone(onStop),
+		},
+	)
+	return nil
+}
+
+func (c *Container) RemoveModuleHooks(path string) {
+	c.moduleHooksMu.Lock()
+	defer c.moduleHooksMu.Unlock()
+
+	c.moduleHooks = slices.DeleteFunc(
+		c.moduleHooks, func(m *moduleHooks) bool {
+			return m.path == path
+		},
+	)
+}
+
+func (c *Container) prepareModuleHooks(ctx context.Context) error {
+	c.moduleHooksMu.Lock()
+	var ready []*moduleHooks
+	for _, m := range c.moduleHooks {
+		m.pending = 0
+		m.started = false
+		m.stop = &sync.Once{}
+		for _, key := range m.members {
+			if c.registry.Has(key) && !c.skipStart(key) {
+				m.pending++
+			}
+		}
+		if m.pending == 0 {
+			ready = append(ready, m)
+		}
+	}
+	c.moduleStopErrs = nil
+	c.moduleHooksMu.Unlock()
+
+	for _, m := range ready {
+		if err := c.startModule(ctx, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Container) memberStarted(ctx context.Context, key string) error {
+	c.moduleHooksMu.Lock()
+	var ready []*moduleHooks
+	for _, m := range c.moduleHooks {
+		if m.pending > 0 && slices.Contains(m.members, key) {
+			m.pending--
+			if m.pending == 0 {
+				ready = append(ready, m)
+			}
+		}
+	}
+	c.moduleHooksMu.Unlock()
+
+	for _, m := range ready {
+		if err := c.startModule(ctx, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Container) startModule(ctx context.Context, m *moduleHooks) error {
+	key := "module:" + m.path
+	for i, hook := range m.onStart {
+		c.logger.Debug("running module OnStart hook", "module", m.path)
+		if err := c.runHook(ctx, key, "OnStart", i, hook, 0); err != nil {
+			return fmt.Errorf("OnStart hook failed for module %s: %w", m.path, err)
+		}
+	}
+
+	c.moduleHooksMu.Lock()
+	m.started = true
+	c.moduleHooksMu.Unlock()
+	return nil
+}
+
+func (c *Container) memberStopping(ctx context.Context, key string) {
+	c.moduleHooksMu.Lock()
+	var stopping []*moduleHooks
+	for _, m := range c.moduleHooks {
+		if m.started && slices.Contains(m.members, key) {
+			stopping = append(stopping, m)
+		}
+	}
+	c.moduleHooksMu.Unlock()
+
+	for _, m := range stopping {
+		m.stop.Do(
+			func() {
+				c.stopModule(ctx, m)
+			},
+		)
+	}
+}
+
+func (c *Container) stopRemainingModules(ctx context.Context) []error {
+	c.moduleHooksMu.Lock()
+	var stopping []*moduleHooks
+	for _, m := range c.moduleHooks {
+		if m.started {
+			stopping = append(stopping, m)
+		}
+	}
+	c.moduleHooksMu.Unlock()
+
+	for _, m := range stopping {
+		m.stop.Do(
+			func() {
+				c.stopModule(ctx, m)
+			},
+		)
+	}
+
+	c.moduleHooksMu.Lock()
+	defer c.moduleHooksMu.Unlock()
+
+	errs := c.moduleStopErrs
+	c.moduleStopErrs = nil
+	return errs
+}
+
+func (c *Container) stopModule(ctx context.Context, m *moduleHooks) {
+	key := "module:" + m.path
+	var failure *StopFailure
+	var hookErrs []error
+
+	for i := len(m.onStop) - 1; i >= 0; i-- {
+		c.logger.Debug("running module OnStop hook", "module", m.path)
+		hookStart := time.Now()
+		if skipped, err := c.runStopHook(ctx, key, i, m.onStop[i], 0); err != nil {
+			if failure == nil {
+				failure = &StopFailure{Key: key, Hook: i, Duration: time.Since(hookStart), Skipped: true}
+			}
+			failure.Skipped = failure.Skipped && skipped
+			hookErrs = append(hookErrs, err)
+		}
+	}
+
+	c.moduleHooksMu.Lock()
+	defer c.moduleHooksMu.Unlock()
+
+	m.started = false
+	if failure != nil {
+		failure.Err = errors.Join(hookErrs...)
+		c.moduleStopErrs = append(c.moduleStopErrs, failure)
+	}
+}
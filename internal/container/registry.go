@@ -2,13 +2,18 @@ package container
 
 import (
 	"context"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/danpasecinic/needle/internal/scope"
 )
 
 type ProviderFunc func(ctx context.Context, r Resolver) (any, error)
 
+type ArgProviderFunc func(ctx context.Context, r Resolver, arg any) (any, error)
+
 type Resolver interface {
 	Resolve(ctx context.Context, key string) (any, error)
 	Has(key string) bool
@@ -19,6 +24,8 @@ type Hook func(ctx context.Context) error
 type ServiceEntry struct {
 	Key          string
 	Provider     ProviderFunc
+	ArgProvider  ArgProviderFunc
+	argCache     *argCache
 	Instance     any
 	Instantiated bool
 	Dependencies []string
@@ -29,6 +36,33 @@ type ServiceEntry struct {
 	pool         chan any
 	Lazy         bool
 	StartRan     bool
+	Template     string
+	Binding      string
+	Primary      bool
+	Priority     int
+	Default      bool
+	Overrides    bool
+	StartTimeout time.Duration
+	StopTimeout  time.Duration
+}
+
+type EntryInfo struct {
+	Key          string
+	Instance     any
+	Instantiated bool
+	Scope        scope.Scope
+	Lazy         bool
+	Assisted     bool
+	Template     string
+	Binding      string
+	Primary      bool
+	Priority     int
+	Default      bool
+	Overrides    bool
+	OnStart      int
+	OnStop       int
+	StartTimeout time.Duration
+	StopTimeout  time.Duration
 }
 
 type Registry struct {
@@ -61,6 +95,14 @@ func (r *Registry) RegisterUnsafe(key string, provider ProviderFunc, dependencie
 	}
 }
 
+func (r *Registry) RegisterArgsUnsafe(key string, provider ArgProviderFunc, dependencies []string) {
+	r.services[key] = &ServiceEntry{
+		Key:          key,
+		ArgProvider:  provider,
+		Dependencies: dependencies,
+	}
+}
+
 func (r *Registry) RegisterValue(key string, value any) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -100,6 +142,22 @@ func (r *Registry) Get(key string) (*ServiceEntry, bool) {
 	return entry, exists
 }
 
+func (r *Registry) GetUnsafe(key string) (*ServiceEntry, bool) {
+	entry, exists := r.services[key]
+	return entry, exists
+}
+
+func (r *Registry) RestoreUnsafe(entry *ServiceEntry) {
+	r.services[entry.Key] = entry
+}
+
+func (r *Registry) SetOriginUnsafe(key string, isDefault bool, overrides bool) {
+	if entry, exists := r.services[key]; exists {
+		entry.Default = isDefault
+		entry.Overrides = entry.Overrides || overrides
+	}
+}
+
 func (r *Registry) GetInstance(key string) (any, bool) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -137,6 +195,45 @@ func (r *Registry) SetInstance(key string, instance any) {
 	}
 }
 
+func (r *Registry) ResetInstance(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if entry, exists := r.services[key]; exists {
+		entry.Instance = nil
+		entry.Instantiated = false
+	}
+}
+
+func (r *Registry) SwapInstances(keys []string, instances map[string]any) []instanceSnapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous := make([]instanceSnapshot, 0, len(keys))
+	for _, key := range keys {
+		entry, exists := r.services[key]
+		if !exists {
+			continue
+		}
+		previous = append(previous, instanceSnapshot{key: key, instance: entry.Instance, instantiated: entry.Instantiated})
+		entry.Instance = instances[key]
+		entry.Instantiated = true
+	}
+	return previous
+}
+
+func (r *Registry) RestoreInstances(snapshots []instanceSnapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, s := range snapshots {
+		if entry, exists := r.services[s.key]; exists {
+			entry.Instance = s.instance
+			entry.Instantiated = s.instantiated
+		}
+	}
+}
+
 func (r *Registry) Keys() []string {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -236,6 +333,42 @@ func (r *Registry) AllEntries() []*ServiceEntry {
 	return entries
 }
 
+func (r *Registry) Snapshot() []EntryInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	infos := make([]EntryInfo, 0, len(r.services))
+	for _, entry := range r.services {
+		infos = append(
+			infos, EntryInfo{
+				Key:          entry.Key,
+				Instance:     entry.Instance,
+				Instantiated: entry.Instantiated,
+				Scope:        entry.Scope,
+				Lazy:         entry.Lazy,
+				Assisted:     entry.ArgProvider != nil,
+				Template:     entry.Template,
+				Binding:      entry.Binding,
+				Primary:      entry.Primary,
+				Priority:     entry.Priority,
+				Default:      entry.Default,
+				Overrides:    entry.Overrides,
+				OnStart:      len(entry.OnStart),
+				OnStop:       len(entry.OnStop),
+				StartTimeout: entry.StartTimeout,
+				StopTimeout:  entry.StopTimeout,
+			},
+		)
+	}
+
+	slices.SortFunc(
+		infos, func(a, b EntryInfo) int {
+			return strings.Compare(a.Key, b.Key)
+		},
+	)
+	return infos
+}
+
 func (r *Registry) SetScope(key string, s scope.Scope) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -291,6 +424,61 @@ func (r *Registry) ReleaseToPool(key string, instance any) bool {
 	}
 }
 
+func (r *Registry) SetArgCacheSize(key string, size int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if entry, exists := r.services[key]; exists && size > 0 {
+		entry.argCache = newArgCache(size)
+	}
+}
+
+func (r *Registry) IsAssisted(key string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if entry, exists := r.services[key]; exists {
+		return entry.ArgProvider != nil
+	}
+	return false
+}
+
+func (r *Registry) SetPrimary(key string, primary bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if entry, exists := r.services[key]; exists {
+		entry.Primary = primary
+	}
+}
+
+func (r *Registry) SetPriority(key string, priority int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if entry, exists := r.services[key]; exists {
+		entry.Priority = priority
+	}
+}
+
+func (r *Registry) SetBinding(key string, implKey string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if entry, exists := r.services[key]; exists {
+		entry.Binding = implKey
+	}
+}
+
+func (r *Registry) SetTemplate(key string, template string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if entry, exists := r.services[key]; exists {
+		entry.Template = template
+	}
+}
+
 func (r *Registry) SetLazy(key string, lazy bool) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -300,6 +488,24 @@ func (r *Registry) SetLazy(key string, lazy bool) {
 	}
 }
 
+func (r *Registry) SetStartTimeout(key string, timeout time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if entry, exists := r.services[key]; exists {
+		entry.StartTimeout = timeout
+	}
+}
+
+func (r *Registry) SetStopTimeout(key string, timeout time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if entry, exists := r.services[key]; exists {
+		entry.StopTimeout = timeout
+	}
+}
+
 func (r *Registry) IsLazy(key string) bool {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -310,6 +516,28 @@ func (r *Registry) IsLazy(key string) bool {
 	return false
 }
 
+func (r *Registry) PrepareRestart(rebuild bool) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var resume []string
+	for key, entry := range r.services {
+		started := entry.StartRan
+		entry.StartRan = false
+
+		if rebuild && entry.Provider != nil && entry.Scope == scope.Singleton && entry.Instantiated {
+			entry.Instance = nil
+			entry.Instantiated = false
+			continue
+		}
+		if entry.Lazy && started && entry.Instantiated {
+			resume = append(resume, key)
+		}
+	}
+	slices.Sort(resume)
+	return resume
+}
+
 func (r *Registry) SetStartRan(key string) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -0,0 +1,187 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/danpasecinic/needle/internal/scope"
+)
+
+type reloadKey struct{}
+
+type instanceSnapshot struct {
+	key          string
+	instance     any
+	instantiated bool
+}
+
+type reloadOverlay struct {
+	mu        sync.Mutex
+	affected  map[string]bool
+	instances map[string]any
+}
+
+func (o *reloadOverlay) get(key string) (any, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	instance, ok := o.instances[key]
+	return instance, ok
+}
+
+func (o *reloadOverlay) set(key string, instance any) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.instances[key] = instance
+}
+
+func getReloadOverlay(ctx context.Context) *reloadOverlay {
+	if o, ok := ctx.Value(reloadKey{}).(*reloadOverlay); ok {
+		return o
+	}
+	return nil
+}
+
+func (c *Container) resolveReloaded(ctx context.Context, key string, overlay *reloadOverlay) (any, error) {
+	if instance, ok := overlay.get(key); ok {
+		return instance, nil
+	}
+
+	entry, exists := c.registry.GetEntry(key)
+	if !exists || entry.Provider == nil || entry.Scope != scope.Singleton {
+		return c.resolveSlow(ctx, key)
+	}
+
+	instance, err := c.resolveTransient(ctx, key, entry)
+	if err != nil {
+		return nil, err
+	}
+	overlay.set(key, instance)
+	return instance, nil
+}
+
+func (c *Container) Reload(ctx context.Context, values map[string]any) ([]string, error) {
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	order, err := c.graph.StartupOrder()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine rebuild order: %w", err)
+	}
+
+	overlay := &reloadOverlay{
+		affected:  make(map[string]bool),
+		instances: make(map[string]any, len(values)),
+	}
+	queue := make([]string, 0, len(values))
+	for key, value := range values {
+		if !c.registry.Has(key) {
+			return nil, fmt.Errorf("service not found: %s", key)
+		}
+		overlay.affected[key] = true
+		overlay.instances[key] = value
+		queue = append(queue, key)
+	}
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		for _, dependent := range c.graph.GetDependents(key) {
+			if !overlay.affected[dependent] {
+				overlay.affected[dependent] = true
+				queue = append(queue, dependent)
+			}
+		}
+	}
+
+	c.reloading.Add(1)
+	buildCtx := context.WithValue(ctx, reloadKey{}, overlay)
+	for _, key := range order {
+		if !overlay.affected[key] {
+			continue
+		}
+		if _, instantiated := c.registry.GetInstance(key); !instantiated {
+			continue
+		}
+		if _, err := c.Resolve(buildCtx, key); err != nil {
+			c.reloading.Add(-1)
+			return nil, fmt.Errorf("failed to rebuild %s: %w", key, err)
+		}
+	}
+	c.reloading.Add(-1)
+
+	var swapped, rebuilt, restart []string
+	running := c.State() == StateRunning
+	for _, key := range order {
+		if _, ok := overlay.instances[key]; !ok {
+			continue
+		}
+		swapped = append(swapped, key)
+		if _, replaced := values[key]; !replaced {
+			rebuilt = append(rebuilt, key)
+		}
+		if entry, ok := c.registry.GetEntry(key); ok && running && entry.StartRan && entry.Instantiated {
+			restart = append(restart, key)
+		}
+	}
+
+	stopped, err := c.stopReloaded(ctx, restart)
+	if err != nil {
+		_, startErr := c.startReloaded(ctx, stopped)
+		return nil, errors.Join(err, startErr)
+	}
+
+	c.mu.Lock()
+	previous := c.registry.SwapInstances(swapped, overlay.instances)
+	c.mu.Unlock()
+
+	started, err := c.startReloaded(ctx, restart)
+	if err != nil {
+		_, stopErr := c.stopReloaded(ctx, started)
+		c.mu.Lock()
+		c.registry.RestoreInstances(previous)
+		c.mu.Unlock()
+		_, startErr := c.startReloaded(ctx, restart)
+		return nil, errors.Join(err, stopErr, startErr)
+	}
+
+	return rebuilt, nil
+}
+
+func (c *Container) stopReloaded(ctx context.Context, keys []string) ([]string, error) {
+	var stopped []string
+	for _, key := range slices.Backward(keys) {
+		entry, ok := c.registry.GetEntry(key)
+		if !ok {
+			continue
+		}
+		for i := len(entry.OnStop) - 1; i >= 0; i-- {
+			if err := c.runHook(ctx, key, "OnStop", i, entry.OnStop[i], entry.StopTimeout); err != nil {
+				return stopped, fmt.Errorf("OnStop hook failed for %s during reload: %w", key, err)
+			}
+		}
+		stopped = append(stopped, key)
+	}
+	slices.Reverse(stopped)
+	return stopped, nil
+}
+
+func (c *Container) startReloaded(ctx context.Context, keys []string) ([]string, error) {
+	var started []string
+	for _, key := range keys {
+		entry, ok := c.registry.GetEntry(key)
+		if !ok {
+			continue
+		}
+		for i, hook := range entry.OnStart {
+			if err := c.runHook(ctx, key, "OnStart", i, hook, entry.StartTimeout); err != nil {
+				return started, fmt.Errorf("OnStart hook failed for %s during reload: %w", key, err)
+			}
+		}
+		started = append(started, key)
+	}
+	return started, nil
+}
@@ -10,6 +10,12 @@ import (
 )
 
 func (c *Container) Resolve(ctx context.Context, key string) (any, error) {
+	if c.reloading.Load() > 0 {
+		if overlay := getReloadOverlay(ctx); overlay != nil && overlay.affected[key] {
+			return c.resolveReloaded(ctx, key, overlay)
+		}
+	}
+
 	if len(c.onResolve) == 0 {
 		if instance, ok := c.registry.GetInstanceFast(key); ok {
 			return instance, nil
@@ -42,17 +48,138 @@ func (c *Container) resolveSlow(ctx context.Context, key string) (any, error) {
 	entry, exists := c.registry.Get(key)
 	c.mu.RUnlock()
 
+	if !exists && c.materialize(key) {
+		entry, exists = c.registry.Get(key)
+	}
+
 	if !exists {
+		if selected, ok := c.Selected(key); ok {
+			result, err := c.Resolve(ctx, selected)
+			c.callResolveHooks(key, time.Since(start), err)
+			return result, err
+		}
+
 		err := fmt.Errorf("service not found: %s", key)
 		c.callResolveHooks(key, time.Since(start), err)
 		return nil, err
 	}
 
+	if entry.ArgProvider != nil {
+		err := fmt.Errorf("service %s requires an argument", key)
+		c.callResolveHooks(key, time.Since(start), err)
+		return nil, err
+	}
+
 	result, err := c.resolveWithScope(ctx, key, entry)
 	c.callResolveHooks(key, time.Since(start), err)
 	return result, err
 }
 
+func (c *Container) Create(ctx context.Context, key string) (any, error) {
+	start := time.Now()
+
+	c.mu.RLock()
+	entry, exists := c.registry.Get(key)
+	c.mu.RUnlock()
+
+	if !exists && c.materialize(key) {
+		entry, exists = c.registry.Get(key)
+	}
+
+	if !exists {
+		if selected, ok := c.Selected(key); ok {
+			key = selected
+			entry, exists = c.registry.Get(selected)
+		}
+	}
+
+	if !exists {
+		err := fmt.Errorf("service not found: %s", key)
+		c.callResolveHooks(key, time.Since(start), err)
+		return nil, err
+	}
+
+	if entry.ArgProvider != nil {
+		err := fmt.Errorf("service %s requires an argument", key)
+		c.callResolveHooks(key, time.Since(start), err)
+		return nil, err
+	}
+
+	if entry.Provider == nil {
+		c.callResolveHooks(key, time.Since(start), nil)
+		return entry.Instance, nil
+	}
+
+	result, err := c.resolveTransient(ctx, key, entry)
+	c.callResolveHooks(key, time.Since(start), err)
+	return result, err
+}
+
+func (c *Container) ResolveWithArg(ctx context.Context, key string, arg any) (any, error) {
+	start := time.Now()
+
+	c.mu.RLock()
+	entry, exists := c.registry.Get(key)
+	c.mu.RUnlock()
+
+	if !exists {
+		if selected, ok := c.Selected(key); ok {
+			key = selected
+			entry, exists = c.registry.Get(selected)
+		}
+	}
+
+	if !exists {
+		err := fmt.Errorf("service not found: %s", key)
+		c.callResolveHooks(key, time.Since(start), err)
+		return nil, err
+	}
+
+	if entry.ArgProvider == nil {
+		err := fmt.Errorf("service %s does not accept arguments", key)
+		c.callResolveHooks(key, time.Since(start), err)
+		return nil, err
+	}
+
+	result, err := c.resolveArg(ctx, key, entry, arg)
+	c.callResolveHooks(key, time.Since(start), err)
+	return result, err
+}
+
+func (c *Container) resolveArg(ctx context.Context, key string, entry *ServiceEntry, arg any) (any, error) {
+	cache := entry.argCache
+	if cache != nil && !hashable(arg) {
+		cache = nil
+	}
+	if cache != nil {
+		if instance, ok := cache.Get(arg); ok {
+			return instance, nil
+		}
+	}
+
+	for _, dep := range entry.Dependencies {
+		if _, err := c.Resolve(ctx, dep); err != nil {
+			return nil, fmt.Errorf("failed to resolve dependency %s for %s: %w", dep, key, err)
+		}
+	}
+
+	instance, err := entry.ArgProvider(ctx, c, arg)
+	if err != nil {
+		return nil, fmt.Errorf("provider failed for %s: %w", key, err)
+	}
+
+	instance, err = c.applyDecorators(ctx, key, instance)
+	if err != nil {
+		return nil, err
+	}
+
+	if cache != nil {
+		cache.Put(arg, instance)
+	}
+
+	return instance, nil
+}
+
 func (c *Container) callResolveHooks(key string, duration time.Duration, err error) {
 	for _, hook := range c.onResolve {
 		hook(key, duration, err)
@@ -97,7 +224,7 @@ func (c *Container) resolveSingleton(ctx context.Context, key string, entry *Ser
 
 	c.registry.SetInstance(key, instance)
 
-	if entry.Lazy && !entry.StartRan && c.state == StateRunning {
+	if entry.Lazy && !entry.StartRan && (c.state == StateStarting || c.state == StateRunning) {
 		if err := c.runLazyStart(ctx, key, entry); err != nil {
 			return nil, err
 		}
@@ -108,18 +235,23 @@ func (c *Container) resolveSingleton(ctx context.Context, key string, entry *Ser
 
 func (c *Container) runLazyStart(ctx context.Context, key string, entry *ServiceEntry) error {
 	start := time.Now()
+	begin := c.beginStart()
 	var startErr error
 
-	for _, hook := range entry.OnStart {
+	for i, hook := range entry.OnStart {
 		c.logger.Debug("running lazy OnStart hook", "service", key)
-		if err := hook(ctx); err != nil {
+		if err := c.runHook(ctx, key, "OnStart", i, hook, entry.StartTimeout); err != nil {
 			startErr = fmt.Errorf("OnStart hook failed for %s: %w", key, err)
 			break
 		}
 	}
+	if startErr == nil {
+		c.recordStarted(key, begin)
+	}
 
 	c.registry.SetStartRan(key)
 	c.callStartHooks(key, time.Since(start), startErr)
+	c.recordTrace(key, "start", 0, start, startErr)
 	return startErr
 }
 
@@ -168,6 +300,10 @@ func WithRequestScope(ctx context.Context) context.Context {
 	return context.WithValue(ctx, requestScopeKey{}, NewRequestScope())
 }
 
+func HasRequestScope(ctx context.Context) bool {
+	return getRequestScope(ctx) != nil
+}
+
 func getRequestScope(ctx context.Context) *RequestScope {
 	if rs, ok := ctx.Value(requestScopeKey{}).(*RequestScope); ok {
 		return rs
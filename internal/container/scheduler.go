@@ -0,0 +1,158 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/danpasecinic/needle/internal/graph"
+)
+
+type scheduledResult struct {
+	key  string
+	lane int
+	err  error
+}
+
+type ScheduledService struct {
+	Key   string
+	After []string
+}
+
+type readySet struct {
+	next    map[string][]string
+	pending map[string]int
+	ready   []string
+}
+
+func newReadySet(schedule *graph.Schedule) *readySet {
+	return &readySet{
+		next:    schedule.Next,
+		pending: maps.Clone(schedule.Pending),
+		ready:   slices.Clone(schedule.Roots),
+	}
+}
+
+func (r *readySet) pop() string {
+	key := r.ready[0]
+	r.ready = r.ready[1:]
+	return key
+}
+
+func (r *readySet) release(key string) {
+	for _, next := range r.next[key] {
+		r.pending[next]--
+		if r.pending[next] == 0 {
+			i, _ := slices.BinarySearch(r.ready, next)
+			r.ready = slices.Insert(r.ready, i, next)
+		}
+	}
+}
+
+func (c *Container) PlanStartup() ([][]ScheduledService, error) {
+	c.syncSelections()
+	schedule, err := c.graph.StartupSchedule()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine startup schedule: %w", err)
+	}
+
+	after := make(map[string][]string, len(schedule.Pending))
+	for key, next := range schedule.Next {
+		for _, n := range next {
+			after[n] = append(after[n], key)
+		}
+	}
+
+	set := newReadySet(schedule)
+	var waves [][]ScheduledService
+	for len(set.ready) > 0 {
+		var wave []ScheduledService
+		var started []string
+		for len(set.ready) > 0 && (c.maxConcurrency <= 0 || len(started) < c.maxConcurrency) {
+			key := set.pop()
+			slices.Sort(after[key])
+			wave = append(wave, ScheduledService{Key: key, After: after[key]})
+			if c.skipStart(key) {
+				set.release(key)
+				continue
+			}
+			started = append(started, key)
+		}
+		for _, key := range started {
+			set.release(key)
+		}
+		waves = append(waves, wave)
+	}
+	return waves, nil
+}
+
+func (c *Container) runSchedule(
+	ctx context.Context,
+	schedule *graph.Schedule,
+	skip func(key string) bool,
+	run func(ctx context.Context, key string, lane int) error,
+	failFast bool,
+) []error {
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	set := newReadySet(schedule)
+	results := make(chan scheduledResult)
+
+	var lanes []bool
+	acquireLane := func() int {
+		for i, busy := range lanes {
+			if !busy {
+				lanes[i] = true
+				return i
+			}
+		}
+		lanes = append(lanes, true)
+		return len(lanes) - 1
+	}
+
+	var errs []error
+	failed, expired := false, false
+	running := 0
+	for {
+		for !failed && len(set.ready) > 0 && (c.maxConcurrency <= 0 || running < c.maxConcurrency) {
+			key := set.pop()
+			if skip(key) {
+				set.release(key)
+				continue
+			}
+			if err := ctx.Err(); err != nil && !failFast && !expired {
+				errs = append(errs, fmt.Errorf("shutdown timeout exceeded: %w", err))
+				expired = true
+			}
+
+			lane := acquireLane()
+			running++
+			go func() {
+				results <- scheduledResult{key: key, lane: lane, err: run(runCtx, key, lane)}
+			}()
+		}
+
+		if running == 0 {
+			return errs
+		}
+
+		result := <-results
+		running--
+		lanes[result.lane] = false
+
+		if result.err != nil {
+			if failed {
+				continue
+			}
+			errs = append(errs, result.err)
+			if failFast {
+				failed = true
+				cancel()
+				continue
+			}
+		}
+		set.release(result.key)
+	}
+}
@@ -0,0 +1,142 @@
+package container
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type selection struct {
+	winner    string
+	conflicts []string
+	primary   bool
+	priority  int
+}
+
+func (c *Container) SetPrimary(key string, primary bool) {
+	c.registry.SetPrimary(key, primary)
+}
+
+func (c *Container) SetPriority(key string, priority int) {
+	c.registry.SetPriority(key, priority)
+}
+
+func (c *Container) Selected(key string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	sel := c.selectUnsafe(key)
+	return sel.winner, sel.winner != ""
+}
+
+func (c *Container) Selections() map[string]string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.selectionsUnsafe()
+}
+
+func (c *Container) syncSelections() {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	c.graph.SetSelected(c.selectionsUnsafe())
+}
+
+func (c *Container) selectionsUnsafe() map[string]string {
+	result := make(map[string]string)
+	for base := range c.candidateBasesUnsafe() {
+		if c.registry.Has(base) {
+			continue
+		}
+		if sel := c.selectUnsafe(base); sel.winner != "" {
+			result[base] = sel.winner
+		}
+	}
+	return result
+}
+
+func (c *Container) selectionConflictsUnsafe() []string {
+	var conflicts []string
+	for base := range c.candidateBasesUnsafe() {
+		sel := c.selectUnsafe(base)
+		switch {
+		case len(sel.conflicts) == 0:
+		case sel.primary:
+			conflicts = append(conflicts, fmt.Sprintf("%s: several primary providers %v", base, sel.conflicts))
+		default:
+			conflicts = append(
+				conflicts, fmt.Sprintf("%s: priority %d tied between %v", base, sel.priority, sel.conflicts),
+			)
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts
+}
+
+func (c *Container) candidateBasesUnsafe() map[string]bool {
+	bases := make(map[string]bool)
+	for _, entry := range c.registry.AllEntries() {
+		if !entry.Primary && entry.Priority == 0 {
+			continue
+		}
+		if base, _, named := strings.Cut(entry.Key, "#"); named {
+			bases[base] = true
+		}
+	}
+	return bases
+}
+
+func (c *Container) selectUnsafe(key string) selection {
+	prefix := key + "#"
+
+	var best []*ServiceEntry
+	for _, entry := range c.registry.AllEntries() {
+		if !strings.HasPrefix(entry.Key, prefix) || (!entry.Primary && entry.Priority == 0) {
+			continue
+		}
+
+		if len(best) == 0 {
+			best = append(best, entry)
+			continue
+		}
+
+		switch compareCandidates(entry, best[0]) {
+		case 1:
+			best = append(best[:0], entry)
+		case 0:
+			best = append(best, entry)
+		}
+	}
+
+	switch len(best) {
+	case 0:
+		return selection{}
+	case 1:
+		return selection{winner: best[0].Key}
+	default:
+		conflicts := make([]string, len(best))
+		for i, entry := range best {
+			conflicts[i] = entry.Key
+		}
+		sort.Strings(conflicts)
+		return selection{conflicts: conflicts, primary: best[0].Primary, priority: best[0].Priority}
+	}
+}
+
+func compareCandidates(a, b *ServiceEntry) int {
+	if a.Primary != b.Primary {
+		if a.Primary {
+			return 1
+		}
+		return -1
+	}
+	switch {
+	case a.Priority > b.Priority:
+		return 1
+	case a.Priority < b.Priority:
+		return -1
+	default:
+		return 0
+	}
+}
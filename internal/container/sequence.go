@@ -0,0 +1,139 @@
+package container
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/danpasecinic/needle/internal/graph"
+)
+
+type startRecord struct {
+	key   string
+	begin uint64
+	end   uint64
+}
+
+func (c *Container) beginStart() uint64 {
+	c.sequenceMu.Lock()
+	defer c.sequenceMu.Unlock()
+
+	c.sequenceClock++
+	return c.sequenceClock
+}
+
+func (c *Container) recordStarted(key string, begin uint64) {
+	c.sequenceMu.Lock()
+	defer c.sequenceMu.Unlock()
+
+	c.sequenceClock++
+	c.started = append(c.started, startRecord{key: key, begin: begin, end: c.sequenceClock})
+}
+
+func (c *Container) resetSequence() {
+	c.sequenceMu.Lock()
+	defer c.sequenceMu.Unlock()
+
+	c.started = nil
+}
+
+func (c *Container) startRecords() []startRecord {
+	c.sequenceMu.Lock()
+	defer c.sequenceMu.Unlock()
+
+	records := slices.Clone(c.started)
+	slices.SortFunc(
+		records, func(a, b startRecord) int {
+			return cmp.Compare(a.begin, b.begin)
+		},
+	)
+	return records
+}
+
+func (c *Container) StartSequence() []string {
+	records := c.startRecords()
+	keys := make([]string, len(records))
+	for i, record := range records {
+		keys[i] = record.key
+	}
+	return keys
+}
+
+func (c *Container) StopSequence() []string {
+	keys := c.StartSequence()
+	slices.Reverse(keys)
+	return keys
+}
+
+type sequenceEvent struct {
+	at    uint64
+	key   string
+	begin bool
+}
+
+func (c *Container) shutdownSchedule() *graph.Schedule {
+	records := c.startRecords()
+	schedule := &graph.Schedule{
+		Next:    make(map[string][]string, len(records)),
+		Pending: make(map[string]int, len(records)),
+	}
+
+	events := make([]sequenceEvent, 0, 2*len(records))
+	for _, record := range records {
+		schedule.Pending[record.key] = 0
+		events = append(
+			events,
+			sequenceEvent{at: record.begin, key: record.key, begin: true},
+			sequenceEvent{at: record.end, key: record.key},
+		)
+	}
+	slices.SortFunc(
+		events, func(a, b sequenceEvent) int {
+			return cmp.Compare(b.at, a.at)
+		},
+	)
+
+	// Sweeping from the latest start backwards, barrier stands for every
+	// service that began after the current point. A service must stop after
+	// all of those, so it waits for the barrier instead of each of them.
+	barrier := ""
+	var began []string
+	for _, event := range events {
+		if event.begin {
+			began = append(began, event.key)
+			continue
+		}
+		if len(began) > 0 {
+			barrier = addBarrier(schedule, barrier, began)
+			began = began[:0]
+		}
+		if barrier != "" {
+			schedule.Next[barrier] = append(schedule.Next[barrier], event.key)
+			schedule.Pending[event.key]++
+		}
+	}
+
+	for key, pending := range schedule.Pending {
+		if pending == 0 {
+			schedule.Roots = append(schedule.Roots, key)
+		}
+	}
+	slices.Sort(schedule.Roots)
+	return schedule
+}
+
+func addBarrier(schedule *graph.Schedule, previous string, began []string) string {
+	if previous == "" && len(began) == 1 {
+		return began[0]
+	}
+
+	barrier := fmt.Sprintf("\x00shutdown:%d", len(schedule.Pending))
+	if previous != "" {
+		began = append(began, previous)
+	}
+	for _, key := range began {
+		schedule.Next[key] = append(schedule.Next[key], barrier)
+	}
+	schedule.Pending[barrier] = len(began)
+	return barrier
+}
@@ -0,0 +1,57 @@
+package container
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type StopFailure struct {
+	Key      string
+	Hook     int
+	Duration time.Duration
+	Err      error
+	Skipped  bool
+}
+
+func (f *StopFailure) Error() string {
+	return fmt.Sprintf("OnStop hook %d failed for %s: %v", f.Hook, f.Key, f.Err)
+}
+
+func (f *StopFailure) Unwrap() error {
+	return f.Err
+}
+
+type ShutdownError struct {
+	Failures []*StopFailure
+	Skipped  []string
+	Errs     []error
+}
+
+func (e *ShutdownError) Error() string {
+	errs := make([]error, 0, len(e.Failures)+len(e.Errs))
+	for _, f := range e.Failures {
+		errs = append(errs, f)
+	}
+	errs = append(errs, e.Errs...)
+	if len(e.Skipped) > 0 {
+		errs = append(errs, fmt.Errorf("skipped after shutdown timeout: %v", e.Skipped))
+	}
+	return fmt.Sprintf("shutdown errors: %v", errs)
+}
+
+func newShutdownError(errs []error) *ShutdownError {
+	result := &ShutdownError{}
+	for _, err := range errs {
+		var failure *StopFailure
+		switch {
+		case !errors.As(err, &failure):
+			result.Errs = append(result.Errs, err)
+		case failure.Skipped:
+			result.Skipped = append(result.Skipped, failure.Key)
+		default:
+			result.Failures = append(result.Failures, failure)
+		}
+	}
+	return result
+}
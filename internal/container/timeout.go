@@ -0,0 +1,115 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const lateStopTimeout = 250 * time.Millisecond
+
+type HookTimeout struct {
+	Service  string
+	Phase    string
+	Index    int
+	Budget   time.Duration
+	Elapsed  time.Duration
+	Returned bool
+}
+
+type hookTimeoutError struct {
+	index  int
+	budget time.Duration
+	err    error
+}
+
+func (e *hookTimeoutError) Error() string {
+	return fmt.Sprintf("hook %d exceeded %s: %v", e.index, e.budget, e.err)
+}
+
+func (e *hookTimeoutError) Unwrap() error {
+	return e.err
+}
+
+func (c *Container) runHook(
+	ctx context.Context, key, phase string, index int, hook Hook, timeout time.Duration,
+) error {
+	deadline, bounded := ctx.Deadline()
+	if timeout <= 0 && !bounded {
+		return hook(ctx)
+	}
+
+	start := time.Now()
+	budget := timeout
+	if bounded && (timeout <= 0 || deadline.Sub(start) < timeout) {
+		budget = max(deadline.Sub(start), 0)
+	}
+
+	hookCtx, cancel := ctx, context.CancelFunc(func() {})
+	if timeout > 0 {
+		hookCtx, cancel = context.WithTimeout(ctx, timeout)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- hook(hookCtx)
+	}()
+
+	select {
+	case err := <-done:
+		cancel()
+		return err
+	case <-hookCtx.Done():
+	}
+
+	record := &HookTimeout{
+		Service: key,
+		Phase:   phase,
+		Index:   index,
+		Budget:  budget,
+		Elapsed: time.Since(start),
+	}
+	c.hookTimeoutsMu.Lock()
+	c.hookTimeouts = append(c.hookTimeouts, record)
+	c.hookTimeoutsMu.Unlock()
+
+	go func() {
+		<-done
+		cancel()
+		c.hookTimeoutsMu.Lock()
+		record.Returned = true
+		record.Elapsed = time.Since(start)
+		c.hookTimeoutsMu.Unlock()
+	}()
+
+	c.logger.Warn("lifecycle hook exceeded its budget", "service", key, "phase", phase, "hook", index, "budget", record.Budget)
+	return &hookTimeoutError{index: index, budget: record.Budget, err: hookCtx.Err()}
+}
+
+func (c *Container) runStopHook(
+	ctx context.Context, key string, index int, hook Hook, timeout time.Duration,
+) (bool, error) {
+	if ctx.Err() == nil {
+		return false, c.runHook(ctx, key, "OnStop", index, hook, timeout)
+	}
+
+	budget := lateStopTimeout
+	if timeout > 0 && timeout < budget {
+		budget = timeout
+	}
+	err := c.runHook(context.WithoutCancel(ctx), key, "OnStop", index, hook, budget)
+	var timeoutErr *hookTimeoutError
+	return errors.As(err, &timeoutErr), err
+}
+
+func (c *Container) HookTimeouts() []HookTimeout {
+	c.hookTimeoutsMu.Lock()
+	defer c.hookTimeoutsMu.Unlock()
+
+	result := make([]HookTimeout, len(c.hookTimeouts))
+	for i, record := range c.hookTimeouts {
+		result[i] = *record
+	}
+	return result
+}
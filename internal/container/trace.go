@@ -0,0 +1,55 @@
+package container
+
+import (
+	"maps"
+	"slices"
+	"time"
+)
+
+type TraceEvent struct {
+	Key   string
+	Phase string
+	Lane  int
+	Begin time.Time
+	End   time.Time
+	Err   error
+}
+
+func (c *Container) recordTrace(key, phase string, lane int, begin time.Time, err error) {
+	c.traceMu.Lock()
+	defer c.traceMu.Unlock()
+
+	c.trace = append(
+		c.trace, TraceEvent{
+			Key:   key,
+			Phase: phase,
+			Lane:  lane,
+			Begin: begin,
+			End:   time.Now(),
+			Err:   err,
+		},
+	)
+}
+
+func (c *Container) Trace() []TraceEvent {
+	c.traceMu.Lock()
+	defer c.traceMu.Unlock()
+
+	return slices.Clone(c.trace)
+}
+
+func (c *Container) recordStartDuration(key string, duration time.Duration) {
+	c.traceMu.Lock()
+	defer c.traceMu.Unlock()
+
+	if c.startDurations != nil {
+		c.startDurations[key] = duration
+	}
+}
+
+func (c *Container) StartDurations() map[string]time.Duration {
+	c.traceMu.Lock()
+	defer c.traceMu.Unlock()
+
+	return maps.Clone(c.startDurations)
+}
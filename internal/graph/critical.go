@@ -0,0 +1,45 @@
+package graph
+
+import "time"
+
+func (g *Graph) CriticalPath(durations map[string]time.Duration) ([]string, time.Duration, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	order, err := g.topologicalSortUnsafe()
+	if err != nil {
+		return nil, 0, err
+	}
+	preds := g.predecessorsUnsafe()
+
+	finish := make(map[string]time.Duration, len(order))
+	previous := make(map[string]string, len(order))
+
+	var end string
+	for _, id := range order {
+		var best time.Duration
+		for _, pred := range preds[id] {
+			if _, exists := g.nodes[pred]; !exists {
+				continue
+			}
+			if f := finish[pred]; f > best || (f == best && f > 0 && pred < previous[id]) {
+				best = f
+				previous[id] = pred
+			}
+		}
+		finish[id] = best + durations[id]
+		if finish[id] > finish[end] || (finish[id] == finish[end] && finish[id] > 0 && id < end) {
+			end = id
+		}
+	}
+
+	if finish[end] == 0 {
+		return nil, 0, nil
+	}
+
+	var path []string
+	for id := end; id != ""; id = previous[id] {
+		path = append([]string{id}, path...)
+	}
+	return path, finish[end], nil
+}
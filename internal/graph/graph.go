@@ -1,6 +1,9 @@
 package graph
 
-import "sync"
+import (
+	"maps"
+	"sync"
+)
 
 type Node struct {
 	ID           string
@@ -11,6 +14,11 @@ type Graph struct {
 	mu         sync.RWMutex
 	nodes      map[string]*Node
 	edges      map[string][]string
+	deferred   map[string][]string
+	after      map[string][]string
+	phases     map[string]string
+	phaseOrder []string
+	selected   map[string]string
 	cycleValid bool
 	hasCycle   bool
 
@@ -21,8 +29,12 @@ type Graph struct {
 
 func New() *Graph {
 	return &Graph{
-		nodes: make(map[string]*Node),
-		edges: make(map[string][]string),
+		nodes:    make(map[string]*Node),
+		edges:    make(map[string][]string),
+		deferred: make(map[string][]string),
+		after:    make(map[string][]string),
+		phases:   make(map[string]string),
+		selected: make(map[string]string),
 	}
 }
 
@@ -59,10 +71,45 @@ func (g *Graph) RemoveNodeUnsafe(id string) {
 func (g *Graph) removeNodeUnsafe(id string) {
 	delete(g.nodes, id)
 	delete(g.edges, id)
+	delete(g.deferred, id)
+	delete(g.after, id)
+	delete(g.phases, id)
 	g.cycleValid = false
 	g.topoValid = false
 }
 
+func (g *Graph) SetDeferred(id string, dependencies []string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.setDeferredUnsafe(id, dependencies)
+}
+
+func (g *Graph) SetDeferredUnsafe(id string, dependencies []string) {
+	g.setDeferredUnsafe(id, dependencies)
+}
+
+func (g *Graph) setDeferredUnsafe(id string, dependencies []string) {
+	if len(dependencies) == 0 {
+		delete(g.deferred, id)
+		return
+	}
+	g.deferred[id] = dependencies
+}
+
+func (g *Graph) GetDeferred(id string) []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	deps, exists := g.deferred[id]
+	if !exists {
+		return nil
+	}
+
+	result := make([]string, len(deps))
+	copy(result, deps)
+	return result
+}
+
 func (g *Graph) HasNode(id string) bool {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
@@ -142,7 +189,12 @@ func (g *Graph) Clear() {
 
 	g.nodes = make(map[string]*Node)
 	g.edges = make(map[string][]string)
+	g.deferred = make(map[string][]string)
+	g.after = make(map[string][]string)
+	g.phases = make(map[string]string)
+	g.selected = make(map[string]string)
 	g.cycleValid = false
+	g.topoValid = false
 }
 
 func (g *Graph) Clone() *Graph {
@@ -159,6 +211,21 @@ func (g *Graph) Clone() *Graph {
 		}
 		clone.edges[id] = deps
 	}
+	for id, deps := range g.deferred {
+		d := make([]string, len(deps))
+		copy(d, deps)
+		clone.deferred[id] = d
+	}
+	for id, after := range g.after {
+		a := make([]string, len(after))
+		copy(a, after)
+		clone.after[id] = a
+	}
+	for id, phase := range g.phases {
+		clone.phases[id] = phase
+	}
+	clone.phaseOrder = append([]string(nil), g.phaseOrder...)
+	clone.selected = maps.Clone(g.selected)
 	return clone
 }
 
@@ -169,11 +236,13 @@ func (g *Graph) Validate() []string {
 	var missing []string
 	seen := make(map[string]bool)
 
-	for _, deps := range g.edges {
-		for _, dep := range deps {
-			if _, exists := g.nodes[dep]; !exists && !seen[dep] {
-				missing = append(missing, dep)
-				seen[dep] = true
+	for _, edges := range []map[string][]string{g.edges, g.deferred} {
+		for _, deps := range edges {
+			for _, dep := range deps {
+				if _, exists := g.nodes[dep]; !exists && !seen[dep] {
+					missing = append(missing, dep)
+					seen[dep] = true
+				}
 			}
 		}
 	}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"slices"
 	"testing"
+	"time"
 )
 
 func TestGraph_AddNode(t *testing.T) {
@@ -66,6 +67,35 @@ func TestGraph_Validate(t *testing.T) {
 	}
 }
 
+func TestGraph_DeferredEdges(t *testing.T) {
+	t.Parallel()
+
+	g := New()
+	g.AddNode("A", nil)
+	g.SetDeferred("A", []string{"B"})
+	g.AddNode("B", []string{"A"})
+
+	if g.HasCycle() {
+		t.Error("deferred edges should not participate in cycle detection")
+	}
+
+	deferred := g.GetDeferred("A")
+	if len(deferred) != 1 || deferred[0] != "B" {
+		t.Errorf("expected deferred edge to B, got %v", deferred)
+	}
+
+	clone := g.Clone()
+	if len(clone.GetDeferred("A")) != 1 {
+		t.Error("clone should keep deferred edges")
+	}
+
+	g.RemoveNode("B")
+	missing := g.Validate()
+	if len(missing) != 1 || missing[0] != "B" {
+		t.Errorf("expected missing deferred dependency B, got %v", missing)
+	}
+}
+
 func TestGraph_Clone(t *testing.T) {
 	t.Parallel()
 
@@ -337,6 +367,67 @@ func TestGraph_ParallelStartupGroups(t *testing.T) {
 	}
 }
 
+func TestGraph_Schedule(t *testing.T) {
+	t.Parallel()
+
+	newGraph := func() *Graph {
+		g := New()
+		g.AddNode("App", []string{"Server", "Worker"})
+		g.AddNode("Server", []string{"Database", "Cache"})
+		g.AddNode("Worker", []string{"Database"})
+		g.AddNode("Database", []string{"Config"})
+		g.AddNode("Cache", []string{"Config"})
+		g.AddNode("Config", nil)
+		return g
+	}
+
+	t.Run("startup", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := newGraph().StartupSchedule()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(s.Roots, []string{"Config"}) {
+			t.Errorf("expected Config as only root, got %v", s.Roots)
+		}
+		if !slices.Equal(s.Next["Database"], []string{"Server", "Worker"}) {
+			t.Errorf("expected Database to release Server and Worker, got %v", s.Next["Database"])
+		}
+		if s.Pending["App"] != 2 || s.Pending["Server"] != 2 || s.Pending["Worker"] != 1 {
+			t.Errorf("unexpected pending counts: %v", s.Pending)
+		}
+	})
+
+	t.Run("ordering constraints", func(t *testing.T) {
+		t.Parallel()
+
+		g := newGraph()
+		g.SetStartAfter("Cache", []string{"Worker"})
+
+		s, err := g.StartupSchedule()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Contains(s.Next["Worker"], "Cache") {
+			t.Errorf("expected Worker to release Cache, got %v", s.Next["Worker"])
+		}
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		t.Parallel()
+
+		g := New()
+		g.AddNode("A", nil)
+		g.AddNode("B", []string{"A"})
+		g.SetStartAfter("A", []string{"B"})
+
+		if _, err := g.StartupSchedule(); !errors.Is(err, ErrCycleDetected) {
+			t.Errorf("expected ErrCycleDetected, got %v", err)
+		}
+	})
+}
+
 func BenchmarkGraph_DetectCycles(b *testing.B) {
 	g := New()
 	for i := 0; i < 100; i++ {
@@ -370,3 +461,123 @@ func BenchmarkGraph_TopologicalSort(b *testing.B) {
 		_, _ = g.TopologicalSort()
 	}
 }
+
+func TestGraph_OrderingConstraints(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"phases and start-after", func(t *testing.T) {
+			t.Parallel()
+
+			g := New()
+			g.SetPhaseOrder([]string{"migrate", "serve"})
+			g.AddNode("http", nil)
+			g.AddNode("grpc", nil)
+			g.AddNode("migrations", nil)
+			g.AddNode("cache", nil)
+			g.AddNode("warmup", nil)
+			g.SetPhase("http", "serve")
+			g.SetPhase("grpc", "serve")
+			g.SetPhase("migrations", "migrate")
+			g.SetStartAfter("warmup", []string{"cache"})
+
+			order, err := g.StartupOrder()
+			if err != nil {
+				t.Fatalf("StartupOrder failed: %v", err)
+			}
+			expected := []string{"cache", "migrations", "grpc", "http", "warmup"}
+			if !slices.Equal(order, expected) {
+				t.Errorf("expected %v, got %v", expected, order)
+			}
+
+			groups, err := g.ParallelStartupGroups()
+			if err != nil {
+				t.Fatalf("ParallelStartupGroups failed: %v", err)
+			}
+			if len(groups) != 2 ||
+				!slices.Equal(groups[0].Nodes, []string{"cache", "migrations"}) ||
+				!slices.Equal(groups[1].Nodes, []string{"grpc", "http", "warmup"}) {
+				t.Errorf("unexpected groups: %+v", groups)
+			}
+		},
+	)
+
+	t.Run(
+		"conflicting constraints form a cycle", func(t *testing.T) {
+			t.Parallel()
+
+			g := New()
+			g.SetPhaseOrder([]string{"migrate", "serve"})
+			g.AddNode("http", nil)
+			g.AddNode("migrations", []string{"http"})
+			g.SetPhase("http", "serve")
+			g.SetPhase("migrations", "migrate")
+
+			if g.HasCycle() {
+				t.Error("dependency graph alone should be acyclic")
+			}
+			if cycle := g.FindOrderingCycle(); len(cycle) != 3 {
+				t.Errorf("expected ordering cycle, got %v", cycle)
+			}
+			if _, err := g.ParallelStartupGroups(); !errors.Is(err, ErrCycleDetected) {
+				t.Errorf("expected cycle error, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"reports undeclared phases and unknown keys", func(t *testing.T) {
+			t.Parallel()
+
+			g := New()
+			g.AddNode("A", nil)
+			g.SetPhase("A", "serve")
+			g.SetStartAfter("A", []string{"B"})
+
+			if phases := g.UndeclaredPhases(); !slices.Equal(phases, []string{"serve"}) {
+				t.Errorf("expected undeclared phase, got %v", phases)
+			}
+			if missing := g.MissingStartAfter(); !slices.Equal(missing, []string{"B"}) {
+				t.Errorf("expected missing key, got %v", missing)
+			}
+		},
+	)
+}
+
+func TestGraph_CriticalPath(t *testing.T) {
+	t.Parallel()
+
+	g := New()
+	g.AddNode("db", nil)
+	g.AddNode("cache", nil)
+	g.AddNode("repo", []string{"db"})
+	g.AddNode("api", []string{"repo", "cache"})
+	g.AddNode("metrics", nil)
+	g.SetStartAfter("metrics", []string{"cache"})
+
+	durations := map[string]time.Duration{
+		"db":      30 * time.Millisecond,
+		"cache":   50 * time.Millisecond,
+		"repo":    10 * time.Millisecond,
+		"api":     5 * time.Millisecond,
+		"metrics": 20 * time.Millisecond,
+	}
+
+	path, total, err := g.CriticalPath(durations)
+	if err != nil {
+		t.Fatalf("CriticalPath failed: %v", err)
+	}
+	if !slices.Equal(path, []string{"cache", "metrics"}) || total != 70*time.Millisecond {
+		t.Errorf("unexpected critical path %v (%s)", path, total)
+	}
+
+	durations["metrics"] = 0
+	path, total, _ = g.CriticalPath(durations)
+	if !slices.Equal(path, []string{"cache", "api"}) || total != 55*time.Millisecond {
+		t.Errorf("unexpected critical path %v (%s)", path, total)
+	}
+
+	if path, _, _ := g.CriticalPath(nil); path != nil {
+		t.Errorf("expected no path without durations, got %v", path)
+	}
+}
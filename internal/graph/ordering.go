@@ -0,0 +1,187 @@
+package graph
+
+import (
+	"maps"
+	"slices"
+	"sort"
+)
+
+func (g *Graph) SetSelected(selected map[string]string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if maps.Equal(g.selected, selected) {
+		return
+	}
+	g.selected = maps.Clone(selected)
+	g.topoValid = false
+}
+
+func (g *Graph) SetStartAfter(id string, after []string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if len(after) == 0 {
+		delete(g.after, id)
+	} else {
+		g.after[id] = after
+	}
+	g.topoValid = false
+}
+
+func (g *Graph) GetStartAfter(id string) []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return slices.Clone(g.after[id])
+}
+
+func (g *Graph) SetPhase(id, phase string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if phase == "" {
+		delete(g.phases, id)
+	} else {
+		g.phases[id] = phase
+	}
+	g.topoValid = false
+}
+
+func (g *Graph) GetPhase(id string) string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.phases[id]
+}
+
+func (g *Graph) SetPhaseOrder(phases []string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.phaseOrder = slices.Clone(phases)
+	g.topoValid = false
+}
+
+func (g *Graph) UndeclaredPhases() []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var undeclared []string
+	for _, phase := range g.phases {
+		if !slices.Contains(g.phaseOrder, phase) && !slices.Contains(undeclared, phase) {
+			undeclared = append(undeclared, phase)
+		}
+	}
+	sort.Strings(undeclared)
+	return undeclared
+}
+
+func (g *Graph) MissingStartAfter() []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var missing []string
+	for _, after := range g.after {
+		for _, key := range after {
+			if _, exists := g.nodes[key]; !exists && !slices.Contains(missing, key) {
+				missing = append(missing, key)
+			}
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+func (g *Graph) FindOrderingCycle() []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	preds := g.predecessorsUnsafe()
+	ids := g.sortedNodesUnsafe()
+
+	state := make(map[string]int, len(ids))
+	var path []string
+
+	var visit func(id string) []string
+	visit = func(id string) []string {
+		switch state[id] {
+		case 1:
+			start := slices.Index(path, id)
+			return append(slices.Clone(path[start:]), id)
+		case 2:
+			return nil
+		}
+
+		state[id] = 1
+		path = append(path, id)
+		for _, pred := range preds[id] {
+			if _, exists := g.nodes[pred]; !exists {
+				continue
+			}
+			if cycle := visit(pred); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = 2
+		return nil
+	}
+
+	for _, id := range ids {
+		if cycle := visit(id); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+func (g *Graph) predecessorsUnsafe() map[string][]string {
+	if len(g.after) == 0 && len(g.phases) == 0 && len(g.selected) == 0 {
+		return g.edges
+	}
+
+	preds := make(map[string][]string, len(g.nodes))
+	for id := range g.nodes {
+		deps := slices.Clone(g.edges[id])
+		for i, dep := range deps {
+			if _, exists := g.nodes[dep]; !exists && g.selected[dep] != "" {
+				deps[i] = g.selected[dep]
+			}
+		}
+		preds[id] = append(deps, g.after[id]...)
+	}
+
+	ranks := make(map[int][]string)
+	for id, phase := range g.phases {
+		if _, exists := g.nodes[id]; !exists {
+			continue
+		}
+		if rank := slices.Index(g.phaseOrder, phase); rank >= 0 {
+			ranks[rank] = append(ranks[rank], id)
+		}
+	}
+
+	var previous []string
+	for rank := range g.phaseOrder {
+		members := ranks[rank]
+		if len(members) == 0 {
+			continue
+		}
+		for _, id := range members {
+			preds[id] = append(preds[id], previous...)
+		}
+		previous = members
+	}
+
+	return preds
+}
+
+func (g *Graph) sortedNodesUnsafe() []string {
+	ids := make([]string, 0, len(g.nodes))
+	for id := range g.nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,56 @@
+package graph
+
+import "sort"
+
+type Schedule struct {
+	Roots   []string
+	Next    map[string][]string
+	Pending map[string]int
+}
+
+func (g *Graph) StartupSchedule() (*Schedule, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	dependents, inDegree := g.orderingUnsafe()
+	return newSchedule(dependents, inDegree, len(g.nodes))
+}
+
+func newSchedule(next map[string][]string, pending map[string]int, size int) (*Schedule, error) {
+	s := &Schedule{
+		Next:    next,
+		Pending: make(map[string]int, len(pending)),
+	}
+
+	remaining := make(map[string]int, len(pending))
+	for id, degree := range pending {
+		s.Pending[id] = degree
+		remaining[id] = degree
+		if degree == 0 {
+			s.Roots = append(s.Roots, id)
+		}
+	}
+	sort.Strings(s.Roots)
+	for _, ids := range next {
+		sort.Strings(ids)
+	}
+
+	queue := append([]string(nil), s.Roots...)
+	visited := 0
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		visited++
+		for _, n := range next[id] {
+			remaining[n]--
+			if remaining[n] == 0 {
+				queue = append(queue, n)
+			}
+		}
+	}
+	if visited != size {
+		return nil, ErrCycleDetected
+	}
+
+	return s, nil
+}
@@ -1,6 +1,10 @@
 package graph
 
-import "errors"
+import (
+	"errors"
+	"slices"
+	"sort"
+)
 
 var ErrCycleDetected = errors.New("cycle detected in graph")
 
@@ -42,40 +46,27 @@ func (g *Graph) TopologicalSort() ([]string, error) {
 }
 
 func (g *Graph) topologicalSortUnsafe() ([]string, error) {
-	nodeCount := len(g.nodes)
-	dependents := make(map[string][]string, nodeCount)
-	inDegree := make(map[string]int, nodeCount)
-
-	for id := range g.nodes {
-		inDegree[id] = 0
-	}
-
-	for id, deps := range g.edges {
-		for _, dep := range deps {
-			if _, exists := g.nodes[dep]; exists {
-				dependents[dep] = append(dependents[dep], id)
-				inDegree[id]++
-			}
-		}
-	}
+	dependents, inDegree := g.orderingUnsafe()
 
-	var queue []string
+	var ready []string
 	for id, degree := range inDegree {
 		if degree == 0 {
-			queue = append(queue, id)
+			ready = append(ready, id)
 		}
 	}
+	sort.Strings(ready)
 
-	var sorted []string
-	for len(queue) > 0 {
-		node := queue[0]
-		queue = queue[1:]
+	sorted := make([]string, 0, len(g.nodes))
+	for len(ready) > 0 {
+		node := ready[0]
+		ready = ready[1:]
 		sorted = append(sorted, node)
 
 		for _, dependent := range dependents[node] {
 			inDegree[dependent]--
 			if inDegree[dependent] == 0 {
-				queue = append(queue, dependent)
+				i, _ := slices.BinarySearch(ready, dependent)
+				ready = slices.Insert(ready, i, dependent)
 			}
 		}
 	}
@@ -87,6 +78,30 @@ func (g *Graph) topologicalSortUnsafe() ([]string, error) {
 	return sorted, nil
 }
 
+func (g *Graph) orderingUnsafe() (map[string][]string, map[string]int) {
+	nodeCount := len(g.nodes)
+	dependents := make(map[string][]string, nodeCount)
+	inDegree := make(map[string]int, nodeCount)
+
+	for id := range g.nodes {
+		inDegree[id] = 0
+	}
+
+	for id, preds := range g.predecessorsUnsafe() {
+		if _, exists := g.nodes[id]; !exists {
+			continue
+		}
+		for _, pred := range preds {
+			if _, exists := g.nodes[pred]; exists {
+				dependents[pred] = append(dependents[pred], id)
+				inDegree[id]++
+			}
+		}
+	}
+
+	return dependents, inDegree
+}
+
 func (g *Graph) ReverseTopologicalSort() ([]string, error) {
 	g.mu.RLock()
 	if g.topoValid && g.topoOrderRev != nil {
@@ -189,59 +204,41 @@ func (g *Graph) ParallelStartupGroups() ([]ParallelGroup, error) {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
 
-	levels := make(map[string]int, len(g.nodes))
+	dependents, inDegree := g.orderingUnsafe()
 
-	var calculateLevel func(id string) int
-	calculateLevel = func(id string) int {
-		if level, ok := levels[id]; ok {
-			return level
-		}
-
-		deps := g.edges[id]
-		if len(deps) == 0 {
-			levels[id] = 0
-			return 0
-		}
-
-		maxDepLevel := -1
-		for _, dep := range deps {
-			if _, exists := g.nodes[dep]; !exists {
-				continue
-			}
-			depLevel := calculateLevel(dep)
-			if depLevel > maxDepLevel {
-				maxDepLevel = depLevel
-			}
+	var current []string
+	for id, degree := range inDegree {
+		if degree == 0 {
+			current = append(current, id)
 		}
-
-		level := maxDepLevel + 1
-		levels[id] = level
-		return level
 	}
 
-	for id := range g.nodes {
-		calculateLevel(id)
-	}
-
-	groupMap := make(map[int][]string)
-	maxLevel := 0
-	for id, level := range levels {
-		groupMap[level] = append(groupMap[level], id)
-		if level > maxLevel {
-			maxLevel = level
+	var groups []ParallelGroup
+	visited := 0
+	for level := 0; len(current) > 0; level++ {
+		sort.Strings(current)
+		groups = append(
+			groups, ParallelGroup{
+				Level: level,
+				Nodes: current,
+			},
+		)
+		visited += len(current)
+
+		var next []string
+		for _, id := range current {
+			for _, dependent := range dependents[id] {
+				inDegree[dependent]--
+				if inDegree[dependent] == 0 {
+					next = append(next, dependent)
+				}
+			}
 		}
+		current = next
 	}
 
-	groups := make([]ParallelGroup, 0, maxLevel+1)
-	for level := 0; level <= maxLevel; level++ {
-		if nodes, ok := groupMap[level]; ok {
-			groups = append(
-				groups, ParallelGroup{
-					Level: level,
-					Nodes: nodes,
-				},
-			)
-		}
+	if visited != len(g.nodes) {
+		return nil, ErrCycleDetected
 	}
 
 	return groups, nil
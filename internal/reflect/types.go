@@ -2,11 +2,13 @@ package reflect
 
 import (
 	"reflect"
+	"strings"
 	"sync"
 )
 
 var typeKeyCache sync.Map
 var namedKeyCache sync.Map
+var keyTypeCache sync.Map
 
 func TypeKey[T any]() string {
 	var zero T
@@ -29,9 +31,39 @@ func typeKeyFromReflect(t reflect.Type) string {
 
 	key := buildTypeKey(t)
 	typeKeyCache.Store(t, key)
+	keyTypeCache.LoadOrStore(key, t)
 	return key
 }
 
+func TypeForKey(key string) (reflect.Type, bool) {
+	if t, ok := keyTypeCache.Load(key); ok {
+		return t.(reflect.Type), true
+	}
+	return nil, false
+}
+
+func GenericKey(t reflect.Type) (string, bool) {
+	if t == nil {
+		return "", false
+	}
+
+	if t.Kind() == reflect.Ptr {
+		key, ok := GenericKey(t.Elem())
+		if !ok {
+			return "", false
+		}
+		return "*" + key, true
+	}
+
+	name := t.Name()
+	idx := strings.IndexByte(name, '[')
+	if idx <= 0 || t.PkgPath() == "" {
+		return "", false
+	}
+
+	return t.PkgPath() + "." + name[:idx] + "[...]", true
+}
+
 func buildTypeKey(t reflect.Type) string {
 	if t == nil {
 		return "<nil>"
@@ -65,6 +97,10 @@ func buildTypeKey(t reflect.Type) string {
 	}
 }
 
+func TypeKeyOf(t reflect.Type) string {
+	return typeKeyFromReflect(t)
+}
+
 func TypeKeyFromValue(v any) string {
 	if v == nil {
 		return "<nil>"
@@ -132,6 +168,7 @@ func Implements[T any](v any) bool {
 type FieldInfo struct {
 	Name     string
 	TypeKey  string
+	Type     reflect.Type
 	Index    int
 	Optional bool
 	Named    string
@@ -157,6 +194,7 @@ func StructFields[T any](tagKey string) ([]FieldInfo, error) {
 		info := FieldInfo{
 			Name:    field.Name,
 			TypeKey: typeKeyFromReflect(field.Type),
+			Type:    field.Type,
 			Index:   i,
 		}
 
@@ -2,6 +2,7 @@ package reflect
 
 import (
 	"context"
+	reflectPkg "reflect"
 	"testing"
 )
 
@@ -223,3 +224,35 @@ func BenchmarkTypeKeyNamed(b *testing.B) {
 		_ = TypeKeyNamed[*testStruct]("primary")
 	}
 }
+
+type genericBox[T any] struct {
+	Value T
+}
+
+func TestGenericKey(t *testing.T) {
+	t.Parallel()
+
+	key, ok := GenericKey(reflectPkg.TypeFor[*genericBox[int]]())
+	if !ok {
+		t.Fatal("expected generic key for instantiation")
+	}
+
+	other, _ := GenericKey(reflectPkg.TypeFor[*genericBox[string]]())
+	if key != other {
+		t.Errorf("expected same template key, got %s and %s", key, other)
+	}
+
+	plain, _ := GenericKey(reflectPkg.TypeFor[genericBox[int]]())
+	if plain == key {
+		t.Error("pointer and value templates should differ")
+	}
+
+	if _, ok := GenericKey(reflectPkg.TypeFor[int]()); ok {
+		t.Error("expected no generic key for int")
+	}
+
+	k := TypeKey[*genericBox[int]]()
+	if typ, ok := TypeForKey(k); !ok || typ != reflectPkg.TypeFor[*genericBox[int]]() {
+		t.Error("expected reverse lookup to find type")
+	}
+}
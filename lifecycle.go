@@ -2,10 +2,113 @@ package needle
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/danpasecinic/needle/internal/container"
 )
 
 type Hook func(ctx context.Context) error
 
+type State int
+
+const (
+	StateNew State = iota
+	StateStarting
+	StateRunning
+	StateStopping
+	StateStopped
+)
+
+var stateNames = map[State]string{
+	StateNew:      "new",
+	StateStarting: "starting",
+	StateRunning:  "running",
+	StateStopping: "stopping",
+	StateStopped:  "stopped",
+}
+
+func (s State) String() string {
+	if name, ok := stateNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("State(%d)", s)
+}
+
+type RestartStrategy int
+
+const (
+	ReuseInstances RestartStrategy = iota
+	RebuildInstances
+)
+
+type HookTimeout struct {
+	Service  string
+	Phase    string
+	Index    int
+	Budget   time.Duration
+	Elapsed  time.Duration
+	Returned bool
+}
+
+type ShutdownError struct {
+	Failures []*Error
+	Skipped  []string
+	Cause    error
+}
+
+func (e *ShutdownError) Error() string {
+	parts := make([]string, 0, len(e.Failures)+2)
+	for _, f := range e.Failures {
+		parts = append(parts, f.Error())
+	}
+	if len(e.Skipped) > 0 {
+		parts = append(parts, fmt.Sprintf("skipped after shutdown timeout: %s", strings.Join(e.Skipped, ", ")))
+	}
+	if e.Cause != nil {
+		parts = append(parts, e.Cause.Error())
+	}
+	return fmt.Sprintf("[%s] shutdown failed: %s", ErrCodeShutdownFailed, strings.Join(parts, "; "))
+}
+
+func (e *ShutdownError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failures)+1)
+	for _, f := range e.Failures {
+		errs = append(errs, f)
+	}
+	if e.Cause != nil {
+		errs = append(errs, e.Cause)
+	}
+	return errs
+}
+
+func (e *ShutdownError) Is(target error) bool {
+	var t *Error
+	return errors.As(target, &t) && t.Code == ErrCodeShutdownFailed
+}
+
+func newShutdownError(runnerErrs []*Error, err error) error {
+	result := &ShutdownError{Failures: runnerErrs}
+
+	var report *container.ShutdownError
+	if errors.As(err, &report) {
+		for _, f := range report.Failures {
+			result.Failures = append(result.Failures, errStopHookFailed(f.Key, f.Hook, f.Duration, f.Err))
+		}
+		result.Skipped = report.Skipped
+		result.Cause = errors.Join(report.Errs...)
+	} else if err != nil {
+		result.Cause = err
+	}
+
+	if len(result.Failures) == 0 && len(result.Skipped) == 0 && result.Cause == nil {
+		return nil
+	}
+	return result
+}
+
 type Lifecycle struct {
 	onStart []Hook
 	onStop  []Hook
@@ -30,3 +133,12 @@ func (l *Lifecycle) OnStop(hook Hook) {
 type LifecycleAware interface {
 	Lifecycle() *Lifecycle
 }
+
+func (c *Container) HookTimeouts() []HookTimeout {
+	records := c.internal.HookTimeouts()
+	result := make([]HookTimeout, len(records))
+	for i, r := range records {
+		result[i] = HookTimeout(r)
+	}
+	return result
+}
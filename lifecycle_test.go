@@ -3,6 +3,8 @@ package needle
 import (
 	"context"
 	"errors"
+	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -838,3 +840,1013 @@ func TestContainer_ParallelShutdown(t *testing.T) {
 		t.Errorf("config should stop last, got %v", stopOrder)
 	}
 }
+
+func TestContainer_StartTimeout(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"per service", func(t *testing.T) {
+			t.Parallel()
+
+			c := New()
+			_ = Provide(
+				c, func(ctx context.Context, r Resolver) (*testService, error) {
+					return &testService{name: "slow"}, nil
+				},
+				WithStartTimeout(20*time.Millisecond),
+				WithOnStart(
+					func(ctx context.Context) error {
+						<-ctx.Done()
+						return ctx.Err()
+					},
+				),
+			)
+
+			err := c.Start(context.Background())
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("expected deadline error, got %v", err)
+			}
+
+			waitFor(t, func() bool {
+				timeouts := c.HookTimeouts()
+				return len(timeouts) == 1 && timeouts[0].Returned
+			})
+			report := c.HookTimeouts()[0]
+			if report.Phase != "OnStart" || report.Budget != 20*time.Millisecond {
+				t.Errorf("unexpected report: %+v", report)
+			}
+		},
+	)
+
+	t.Run(
+		"container wide reports hooks that never return", func(t *testing.T) {
+			t.Parallel()
+
+			release := make(chan struct{})
+			defer close(release)
+
+			c := New(WithStartupTimeout(20 * time.Millisecond))
+			_ = Provide(
+				c, func(ctx context.Context, r Resolver) (*testService, error) {
+					return &testService{name: "stuck"}, nil
+				},
+				WithOnStart(
+					func(ctx context.Context) error {
+						<-release
+						return nil
+					},
+				),
+			)
+
+			if err := c.Start(context.Background()); err == nil {
+				t.Fatal("expected startup timeout")
+			}
+
+			timeouts := c.HookTimeouts()
+			if len(timeouts) != 1 || timeouts[0].Returned {
+				t.Fatalf("expected one hook that never returned, got %+v", timeouts)
+			}
+			if timeouts[0].Service != reflect.TypeKey[*testService]() {
+				t.Errorf("unexpected service: %s", timeouts[0].Service)
+			}
+		},
+	)
+}
+
+func TestContainer_StopTimeoutBestEffort(t *testing.T) {
+	t.Parallel()
+
+	c := New(WithShutdownTimeout(50 * time.Millisecond))
+
+	release := make(chan struct{})
+	defer close(release)
+
+	var dbStopped atomic.Bool
+	_ = Provide(
+		c, func(ctx context.Context, r Resolver) (*testDatabase, error) {
+			return &testDatabase{}, nil
+		},
+		WithOnStop(
+			func(ctx context.Context) error {
+				dbStopped.Store(true)
+				return nil
+			},
+		),
+	)
+	_ = Provide(
+		c, func(ctx context.Context, r Resolver) (*testServer, error) {
+			_, err := Invoke[*testDatabase](c)
+			return &testServer{}, err
+		},
+		WithDependencies(reflect.TypeKey[*testDatabase]()),
+		WithStopTimeout(10*time.Millisecond),
+		WithOnStop(
+			func(ctx context.Context) error {
+				<-release
+				return nil
+			},
+		),
+	)
+
+	_ = c.Start(context.Background())
+
+	if err := c.Stop(context.Background()); err == nil {
+		t.Error("expected stop timeout error")
+	}
+	if !dbStopped.Load() {
+		t.Error("remaining services should still be stopped")
+	}
+
+	timeouts := c.HookTimeouts()
+	if len(timeouts) != 1 || timeouts[0].Phase != "OnStop" || timeouts[0].Returned {
+		t.Errorf("expected stuck OnStop hook, got %+v", timeouts)
+	}
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestContainer_Phases(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"orders startup across phases", func(t *testing.T) {
+			t.Parallel()
+
+			for _, parallel := range []bool{false, true} {
+				opts := []Option{WithPhases("migrate", "serve")}
+				if parallel {
+					opts = append(opts, WithParallel())
+				}
+				c := New(opts...)
+
+				var mu sync.Mutex
+				var order []string
+				record := func(name string) Hook {
+					return func(ctx context.Context) error {
+						mu.Lock()
+						defer mu.Unlock()
+						order = append(order, name)
+						return nil
+					}
+				}
+
+				_ = ProvideValue(c, &testServer{}, WithPhase("serve"), WithOnStart(record("server")))
+				_ = ProvideValue(c, &testDatabase{}, WithPhase("migrate"), WithOnStart(record("migrations")))
+				_ = ProvideValue(
+					c, &testConfig{}, WithStartAfter(reflect.TypeKey[*testServer]()), WithOnStart(record("announce")),
+				)
+
+				if err := c.Validate(); err != nil {
+					t.Fatalf("Validate failed: %v", err)
+				}
+				if err := c.Start(context.Background()); err != nil {
+					t.Fatalf("Start failed: %v", err)
+				}
+				expected := []string{"migrations", "server", "announce"}
+				if !slices.Equal(order, expected) {
+					t.Errorf("parallel=%v: expected %v, got %v", parallel, expected, order)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"validate detects conflicting constraints", func(t *testing.T) {
+			t.Parallel()
+
+			c := New(WithPhases("migrate", "serve"))
+			_ = ProvideValue(c, &testServer{}, WithPhase("serve"))
+			_ = Provide(
+				c, func(ctx context.Context, r Resolver) (*testDatabase, error) {
+					return &testDatabase{}, nil
+				},
+				WithPhase("migrate"),
+				WithDependencies(reflect.TypeKey[*testServer]()),
+			)
+
+			err := c.Validate()
+			if err == nil || !strings.Contains(err.Error(), "conflicting startup ordering constraints") {
+				t.Errorf("expected ordering conflict, got %v", err)
+			}
+		},
+	)
+}
+
+func TestContainer_ParallelScheduler(t *testing.T) {
+	t.Parallel()
+
+	provideNamed := func(c *Container, name string, deps []string, opts ...ProviderOption) {
+		keys := make([]string, len(deps))
+		for i, dep := range deps {
+			keys[i] = reflect.TypeKeyNamed[*testConfig](dep)
+		}
+		opts = append(opts, WithDependencies(keys...))
+		_ = ProvideNamed(
+			c, name, func(ctx context.Context, r Resolver) (*testConfig, error) {
+				return &testConfig{value: name}, nil
+			},
+			opts...,
+		)
+	}
+
+	t.Run(
+		"starts services without level barriers", func(t *testing.T) {
+			t.Parallel()
+
+			c := New(WithParallel())
+
+			var slowDone, fastStarted atomic.Int64
+			provideNamed(
+				c, "slow", nil, WithOnStart(
+					func(ctx context.Context) error {
+						time.Sleep(50 * time.Millisecond)
+						slowDone.Store(time.Now().UnixNano())
+						return nil
+					},
+				),
+			)
+			provideNamed(c, "fast", nil)
+			provideNamed(
+				c, "after-fast", []string{"fast"}, WithOnStart(
+					func(ctx context.Context) error {
+						fastStarted.Store(time.Now().UnixNano())
+						return nil
+					},
+				),
+			)
+			provideNamed(c, "after-slow", []string{"slow"})
+
+			if err := c.Start(context.Background()); err != nil {
+				t.Fatalf("Start failed: %v", err)
+			}
+			if fastStarted.Load() >= slowDone.Load() {
+				t.Error("after-fast should start before slow finishes")
+			}
+			_ = c.Stop(context.Background())
+		},
+	)
+
+	t.Run(
+		"limits concurrency", func(t *testing.T) {
+			t.Parallel()
+
+			c := New(WithParallel(), WithMaxConcurrency(2))
+
+			var running, peak atomic.Int32
+			hook := func(ctx context.Context) error {
+				n := running.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				running.Add(-1)
+				return nil
+			}
+			for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+				provideNamed(c, name, nil, WithOnStart(hook), WithOnStop(hook))
+			}
+
+			if err := c.Start(context.Background()); err != nil {
+				t.Fatalf("Start failed: %v", err)
+			}
+			if err := c.Stop(context.Background()); err != nil {
+				t.Fatalf("Stop failed: %v", err)
+			}
+			if peak.Load() != 2 {
+				t.Errorf("expected peak concurrency of 2, got %d", peak.Load())
+			}
+			for _, event := range c.Trace().Events {
+				if event.Lane > 1 {
+					t.Errorf("expected lanes 0 and 1 only, got %d for %s", event.Lane, event.Service)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"cancels in-flight starts on first error", func(t *testing.T) {
+			t.Parallel()
+
+			c := New(WithParallel())
+
+			var cancelled, dependentStarted atomic.Bool
+			provideNamed(
+				c, "blocked", nil, WithOnStart(
+					func(ctx context.Context) error {
+						select {
+						case <-ctx.Done():
+							cancelled.Store(true)
+							return ctx.Err()
+						case <-time.After(time.Second):
+							return nil
+						}
+					},
+				),
+			)
+			provideNamed(
+				c, "broken", nil, WithOnStart(
+					func(ctx context.Context) error {
+						time.Sleep(5 * time.Millisecond)
+						return errors.New("boom")
+					},
+				),
+			)
+			provideNamed(
+				c, "dependent", []string{"blocked"}, WithOnStart(
+					func(ctx context.Context) error {
+						dependentStarted.Store(true)
+						return nil
+					},
+				),
+			)
+
+			begin := time.Now()
+			err := c.Start(context.Background())
+			if err == nil || !strings.Contains(err.Error(), "boom") {
+				t.Fatalf("expected boom error, got %v", err)
+			}
+			if elapsed := time.Since(begin); elapsed > 500*time.Millisecond {
+				t.Errorf("Start should fail fast, took %v", elapsed)
+			}
+			if !cancelled.Load() {
+				t.Error("in-flight start should be cancelled")
+			}
+			if dependentStarted.Load() {
+				t.Error("dependent should not start after a failure")
+			}
+		},
+	)
+
+	t.Run(
+		"stops services whose starts overlapped concurrently", func(t *testing.T) {
+			t.Parallel()
+
+			c := New(WithParallel())
+
+			var entered sync.WaitGroup
+			entered.Add(2)
+			overlap := func(ctx context.Context) error {
+				entered.Done()
+				entered.Wait()
+				return nil
+			}
+
+			var slowDone, fastStopped atomic.Int64
+			provideNamed(
+				c, "slow", nil, WithOnStart(overlap), WithOnStop(
+					func(ctx context.Context) error {
+						time.Sleep(50 * time.Millisecond)
+						slowDone.Store(time.Now().UnixNano())
+						return nil
+					},
+				),
+			)
+			provideNamed(
+				c, "fast", nil, WithOnStart(overlap), WithOnStop(
+					func(ctx context.Context) error {
+						fastStopped.Store(time.Now().UnixNano())
+						return nil
+					},
+				),
+			)
+
+			if err := c.Start(context.Background()); err != nil {
+				t.Fatalf("Start failed: %v", err)
+			}
+			if err := c.Stop(context.Background()); err != nil {
+				t.Fatalf("Stop failed: %v", err)
+			}
+			if fastStopped.Load() >= slowDone.Load() {
+				t.Error("fast should stop while slow is still stopping")
+			}
+		},
+	)
+}
+
+func TestContainer_ShutdownError(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"reports each failed service", func(t *testing.T) {
+			t.Parallel()
+
+			errDatabase := errors.New("database close failed")
+			errServer := errors.New("server drain failed")
+
+			c := New()
+			_ = ProvideValue(
+				c, &testDatabase{}, WithOnStop(
+					func(ctx context.Context) error {
+						return errDatabase
+					},
+				),
+			)
+			_ = ProvideValue(
+				c, &testServer{},
+				WithOnStop(
+					func(ctx context.Context) error {
+						return nil
+					},
+				),
+				WithOnStop(
+					func(ctx context.Context) error {
+						time.Sleep(5 * time.Millisecond)
+						return errServer
+					},
+				),
+			)
+			_ = ProvideValue(c, &testConfig{})
+
+			if err := c.Start(context.Background()); err != nil {
+				t.Fatalf("Start failed: %v", err)
+			}
+			err := c.Stop(context.Background())
+
+			var shutdownErr *ShutdownError
+			if !errors.As(err, &shutdownErr) {
+				t.Fatalf("expected *ShutdownError, got %T: %v", err, err)
+			}
+			if len(shutdownErr.Failures) != 2 || len(shutdownErr.Skipped) != 0 {
+				t.Fatalf("expected 2 failures and no skipped services, got %+v", shutdownErr)
+			}
+			if !IsShutdownFailed(err) || !errors.Is(err, errDatabase) || !errors.Is(err, errServer) {
+				t.Errorf("expected causes to match with errors.Is: %v", err)
+			}
+
+			for _, failure := range shutdownErr.Failures {
+				if failure.Code != ErrCodeShutdownFailed {
+					t.Errorf("expected SHUTDOWN_FAILED, got %s", failure.Code)
+				}
+				switch failure.Service {
+				case reflect.TypeKey[*testServer]():
+					if failure.Hook != 1 || failure.Duration < 5*time.Millisecond {
+						t.Errorf("expected hook 1 taking at least 5ms, got hook %d after %s", failure.Hook, failure.Duration)
+					}
+				case reflect.TypeKey[*testDatabase]():
+					if failure.Hook != 0 {
+						t.Errorf("expected hook 0, got %d", failure.Hook)
+					}
+				default:
+					t.Errorf("unexpected failed service %s", failure.Service)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"lists services skipped after the deadline", func(t *testing.T) {
+			t.Parallel()
+
+			c := New(WithShutdownTimeout(20 * time.Millisecond))
+			_ = ProvideValue(
+				c, &testDatabase{}, WithOnStop(
+					func(ctx context.Context) error {
+						<-ctx.Done()
+						return ctx.Err()
+					},
+				),
+			)
+			_ = Provide(
+				c, func(ctx context.Context, r Resolver) (*testServer, error) {
+					return &testServer{}, nil
+				},
+				WithDependencies(reflect.TypeKey[*testDatabase]()),
+				WithOnStop(
+					func(ctx context.Context) error {
+						<-ctx.Done()
+						return ctx.Err()
+					},
+				),
+			)
+
+			if err := c.Start(context.Background()); err != nil {
+				t.Fatalf("Start failed: %v", err)
+			}
+			err := c.Stop(context.Background())
+
+			var shutdownErr *ShutdownError
+			if !errors.As(err, &shutdownErr) {
+				t.Fatalf("expected *ShutdownError, got %T: %v", err, err)
+			}
+			if len(shutdownErr.Failures) != 1 || shutdownErr.Failures[0].Service != reflect.TypeKey[*testServer]() {
+				t.Errorf("expected server to fail, got %v", shutdownErr.Failures)
+			}
+			if !slices.Equal(shutdownErr.Skipped, []string{reflect.TypeKey[*testDatabase]()}) {
+				t.Errorf("expected database to be skipped, got %v", shutdownErr.Skipped)
+			}
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("expected deadline exceeded cause, got %v", err)
+			}
+
+			timeouts := c.HookTimeouts()
+			if len(timeouts) != 2 || timeouts[1].Budget != 250*time.Millisecond {
+				t.Errorf("expected the late hook to get the fallback budget, got %+v", timeouts)
+			}
+		},
+	)
+
+	t.Run(
+		"waits for late stops in order", func(t *testing.T) {
+			t.Parallel()
+
+			var mu sync.Mutex
+			var stopOrder []string
+			record := func(name string) Hook {
+				return func(ctx context.Context) error {
+					time.Sleep(10 * time.Millisecond)
+					mu.Lock()
+					stopOrder = append(stopOrder, name)
+					mu.Unlock()
+					return nil
+				}
+			}
+
+			c := New(WithShutdownTimeout(20 * time.Millisecond))
+			_ = ProvideValue(c, &testConfig{}, WithOnStop(record("config")))
+			_ = ProvideValue(
+				c, &testDatabase{},
+				WithDependencies(reflect.TypeKey[*testConfig]()),
+				WithOnStop(record("database")),
+			)
+			_ = ProvideValue(
+				c, &testServer{},
+				WithDependencies(reflect.TypeKey[*testDatabase]()),
+				WithOnStop(
+					func(ctx context.Context) error {
+						<-ctx.Done()
+						return ctx.Err()
+					},
+				),
+			)
+
+			if err := c.Start(context.Background()); err != nil {
+				t.Fatalf("Start failed: %v", err)
+			}
+			err := c.Stop(context.Background())
+
+			mu.Lock()
+			defer mu.Unlock()
+			if !slices.Equal(stopOrder, []string{"database", "config"}) {
+				t.Errorf("expected late stops to finish in reverse order before Stop returns, got %v", stopOrder)
+			}
+			var shutdownErr *ShutdownError
+			if !errors.As(err, &shutdownErr) || len(shutdownErr.Skipped) != 0 || len(shutdownErr.Failures) != 1 {
+				t.Errorf("expected only the server to fail, got %v", err)
+			}
+		},
+	)
+}
+
+func TestContainer_Restart(t *testing.T) {
+	t.Parallel()
+
+	type counters struct {
+		builds, starts, stops atomic.Int32
+	}
+	provideCounted := func(c *Container, n *counters, opts ...ProviderOption) {
+		opts = append(
+			opts,
+			WithOnStart(
+				func(ctx context.Context) error {
+					n.starts.Add(1)
+					return nil
+				},
+			),
+			WithOnStop(
+				func(ctx context.Context) error {
+					n.stops.Add(1)
+					return nil
+				},
+			),
+		)
+		_ = Provide(
+			c, func(ctx context.Context, r Resolver) (*testService, error) {
+				n.builds.Add(1)
+				return &testService{name: "counted"}, nil
+			},
+			opts...,
+		)
+	}
+
+	t.Run(
+		"reuses instances by default", func(t *testing.T) {
+			t.Parallel()
+
+			c := New()
+			var n counters
+			provideCounted(c, &n)
+			_ = ProvideValue(c, &testConfig{value: "static"})
+
+			ctx := context.Background()
+			if err := c.Start(ctx); err != nil {
+				t.Fatalf("Start failed: %v", err)
+			}
+			before := MustInvoke[*testService](c)
+
+			if err := c.Restart(ctx); err != nil {
+				t.Fatalf("Restart failed: %v", err)
+			}
+			if after := MustInvoke[*testService](c); after != before {
+				t.Error("expected the instance to be reused")
+			}
+			if n.builds.Load() != 1 || n.starts.Load() != 2 || n.stops.Load() != 1 {
+				t.Errorf("expected 1 build, 2 starts, 1 stop, got %d/%d/%d", n.builds.Load(), n.starts.Load(), n.stops.Load())
+			}
+			if c.State() != StateRunning {
+				t.Errorf("expected running state, got %s", c.State())
+			}
+		},
+	)
+
+	t.Run(
+		"rebuilds singletons", func(t *testing.T) {
+			t.Parallel()
+
+			c := New(WithRestartStrategy(RebuildInstances))
+			var n counters
+			provideCounted(c, &n)
+			config := &testConfig{value: "static"}
+			_ = ProvideValue(c, config)
+
+			ctx := context.Background()
+			if err := c.Start(ctx); err != nil {
+				t.Fatalf("Start failed: %v", err)
+			}
+			before := MustInvoke[*testService](c)
+
+			if err := c.Restart(ctx); err != nil {
+				t.Fatalf("Restart failed: %v", err)
+			}
+			if after := MustInvoke[*testService](c); after == before {
+				t.Error("expected a new instance after rebuild")
+			}
+			if MustInvoke[*testConfig](c) != config {
+				t.Error("values should survive a rebuild")
+			}
+			if n.builds.Load() != 2 || n.starts.Load() != 2 || n.stops.Load() != 1 {
+				t.Errorf("expected 2 builds, 2 starts, 1 stop, got %d/%d/%d", n.builds.Load(), n.starts.Load(), n.stops.Load())
+			}
+		},
+	)
+
+	t.Run(
+		"restarts lazy services that were in use", func(t *testing.T) {
+			t.Parallel()
+
+			for _, strategy := range []RestartStrategy{ReuseInstances, RebuildInstances} {
+				c := New(WithRestartStrategy(strategy))
+				var n counters
+				provideCounted(c, &n, WithLazy())
+
+				ctx := context.Background()
+				_ = c.Start(ctx)
+				_ = MustInvoke[*testService](c)
+				if err := c.Restart(ctx); err != nil {
+					t.Fatalf("Restart failed: %v", err)
+				}
+
+				wantStarts := int32(2)
+				if strategy == RebuildInstances {
+					wantStarts = 1
+				}
+				if n.starts.Load() != wantStarts || n.stops.Load() != 1 {
+					t.Errorf(
+						"strategy %d: expected %d starts and 1 stop after restart, got %d/%d",
+						strategy, wantStarts, n.starts.Load(), n.stops.Load(),
+					)
+				}
+
+				_ = MustInvoke[*testService](c)
+				if n.starts.Load() != 2 {
+					t.Errorf("strategy %d: expected 2 starts after use, got %d", strategy, n.starts.Load())
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"notifies state observers", func(t *testing.T) {
+			t.Parallel()
+
+			var mu sync.Mutex
+			var transitions []string
+			c := New(
+				WithStateObserver(
+					func(from, to State) {
+						mu.Lock()
+						defer mu.Unlock()
+						transitions = append(transitions, from.String()+"->"+to.String())
+					},
+				),
+			)
+			_ = ProvideValue(c, &testConfig{})
+
+			if c.State() != StateNew {
+				t.Errorf("expected new state, got %s", c.State())
+			}
+			ctx := context.Background()
+			_ = c.Start(ctx)
+			_ = c.Restart(ctx)
+			_ = c.Stop(ctx)
+
+			expected := []string{
+				"new->starting", "starting->running",
+				"running->stopping", "stopping->stopped", "stopped->starting", "starting->running",
+				"running->stopping", "stopping->stopped",
+			}
+			if !slices.Equal(transitions, expected) {
+				t.Errorf("expected %v, got %v", expected, transitions)
+			}
+			if c.State() != StateStopped {
+				t.Errorf("expected stopped state, got %s", c.State())
+			}
+		},
+	)
+
+	t.Run(
+		"cleans up a failed start", func(t *testing.T) {
+			t.Parallel()
+
+			var mu sync.Mutex
+			var events []string
+			record := func(event string) Hook {
+				return func(ctx context.Context) error {
+					mu.Lock()
+					defer mu.Unlock()
+					events = append(events, event)
+					return nil
+				}
+			}
+
+			c := New(
+				WithStateObserver(
+					func(from, to State) {
+						record(from.String() + "->" + to.String())(context.Background())
+					},
+				),
+			)
+			module := NewModule("storage").
+				OnStart(record("module:start")).
+				OnStop(record("module:stop"))
+			ModuleProvideValue(
+				module, &testConfig{},
+				WithOnStart(record("config:start")),
+				WithOnStop(record("config:stop")),
+			)
+			if err := c.Apply(module); err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+
+			var failing atomic.Bool
+			failing.Store(true)
+			_ = ProvideValue(
+				c, &testServer{},
+				WithDependencies(reflect.TypeKey[*testConfig]()),
+				WithOnStart(
+					func(ctx context.Context) error {
+						if failing.Load() {
+							return errors.New("port in use")
+						}
+						return record("server:start")(ctx)
+					},
+				),
+				WithOnStop(record("server:stop")),
+			)
+
+			ctx := context.Background()
+			if err := c.Start(ctx); err == nil {
+				t.Fatal("expected Start to fail")
+			}
+			if c.State() != StateStopped {
+				t.Errorf("expected stopped state after a failed start, got %s", c.State())
+			}
+
+			mu.Lock()
+			expected := []string{
+				"new->starting", "config:start", "module:start",
+				"starting->stopping", "module:stop", "config:stop", "stopping->stopped",
+			}
+			if !slices.Equal(events, expected) {
+				t.Errorf("expected %v, got %v", expected, events)
+			}
+			events = nil
+			mu.Unlock()
+
+			failing.Store(false)
+			if err := c.Restart(ctx); err != nil {
+				t.Fatalf("Restart failed: %v", err)
+			}
+			if c.State() != StateRunning {
+				t.Errorf("expected running state, got %s", c.State())
+			}
+			if err := c.Stop(ctx); err != nil {
+				t.Fatalf("Stop failed: %v", err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if !slices.Contains(events, "server:start") || !slices.Contains(events, "server:stop") {
+				t.Errorf("expected the server to start and stop after Restart, got %v", events)
+			}
+		},
+	)
+
+	t.Run(
+		"stops after a startup timeout", func(t *testing.T) {
+			t.Parallel()
+
+			var stopped atomic.Bool
+			c := New(
+				WithStartupTimeout(20*time.Millisecond),
+				WithShutdownTimeout(time.Second),
+			)
+			_ = ProvideValue(
+				c, &testConfig{},
+				WithOnStop(
+					func(ctx context.Context) error {
+						select {
+						case <-time.After(300 * time.Millisecond):
+							stopped.Store(true)
+							return nil
+						case <-ctx.Done():
+							return ctx.Err()
+						}
+					},
+				),
+			)
+			_ = ProvideValue(
+				c, &testServer{},
+				WithDependencies(reflect.TypeKey[*testConfig]()),
+				WithOnStart(
+					func(ctx context.Context) error {
+						<-ctx.Done()
+						return ctx.Err()
+					},
+				),
+			)
+
+			err := c.Start(context.Background())
+			if err == nil {
+				t.Fatal("expected Start to fail")
+			}
+			if !stopped.Load() {
+				t.Errorf("expected the config to finish stopping, got %v", err)
+			}
+			if strings.Contains(err.Error(), "shutdown") {
+				t.Errorf("expected no shutdown error, got %v", err)
+			}
+		},
+	)
+}
+
+func TestContainer_LazyShutdownOrder(t *testing.T) {
+	t.Parallel()
+
+	for _, parallel := range []bool{false, true} {
+		name := "sequential"
+		if parallel {
+			name = "parallel"
+		}
+
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+
+				var opts []Option
+				if parallel {
+					opts = append(opts, WithParallel())
+				}
+				c := New(opts...)
+
+				var mu sync.Mutex
+				var started, stopped []string
+				provide := func(name string, lazy bool, deps ...string) {
+					keys := make([]string, len(deps))
+					for i, dep := range deps {
+						keys[i] = reflect.TypeKeyNamed[*testConfig](dep)
+					}
+					providerOpts := []ProviderOption{
+						WithDependencies(keys...),
+						WithOnStart(
+							func(ctx context.Context) error {
+								mu.Lock()
+								defer mu.Unlock()
+								started = append(started, name)
+								return nil
+							},
+						),
+						WithOnStop(
+							func(ctx context.Context) error {
+								mu.Lock()
+								defer mu.Unlock()
+								stopped = append(stopped, name)
+								return nil
+							},
+						),
+					}
+					if lazy {
+						providerOpts = append(providerOpts, WithLazy())
+					}
+					_ = ProvideNamed(
+						c, name, func(ctx context.Context, r Resolver) (*testConfig, error) {
+							for _, dep := range deps {
+								if _, err := InvokeNamed[*testConfig](c, dep); err != nil {
+									return nil, err
+								}
+							}
+							return &testConfig{value: name}, nil
+						},
+						providerOpts...,
+					)
+				}
+
+				provide("a", false)
+				provide("b", false, "a")
+				provide("lazy-dep", true)
+				provide("c", false, "lazy-dep")
+				provide("early", true)
+
+				ctx := context.Background()
+				if err := c.Start(ctx); err != nil {
+					t.Fatalf("Start failed: %v", err)
+				}
+				if _, err := InvokeNamed[*testConfig](c, "early"); err != nil {
+					t.Fatalf("Invoke failed: %v", err)
+				}
+				if err := c.Stop(ctx); err != nil {
+					t.Fatalf("Stop failed: %v", err)
+				}
+
+				if !slices.Contains(started, "lazy-dep") {
+					t.Fatalf("lazy dependency of an eager service should start, got %v", started)
+				}
+				if len(stopped) != len(started) {
+					t.Fatalf("expected every started service to stop once, started %v, stopped %v", started, stopped)
+				}
+
+				before := func(first, second string) {
+					t.Helper()
+					if slices.Index(stopped, first) > slices.Index(stopped, second) {
+						t.Errorf("expected %s to stop before %s, got %v", first, second, stopped)
+					}
+				}
+				for _, name := range []string{"a", "b", "c", "lazy-dep"} {
+					before("early", name)
+				}
+				before("c", "lazy-dep")
+				before("b", "a")
+
+				if !parallel {
+					reversed := slices.Clone(started)
+					slices.Reverse(reversed)
+					if !slices.Equal(stopped, reversed) {
+						t.Errorf("expected exact reverse of %v, got %v", started, stopped)
+					}
+				}
+			},
+		)
+	}
+}
+
+func TestContainer_LazyStartedBeforeStartIsNotStopped(t *testing.T) {
+	t.Parallel()
+
+	c := New()
+
+	var stopped atomic.Bool
+	_ = Provide(
+		c, func(ctx context.Context, r Resolver) (*testService, error) {
+			return &testService{name: "lazy"}, nil
+		},
+		WithLazy(),
+		WithOnStop(
+			func(ctx context.Context) error {
+				stopped.Store(true)
+				return nil
+			},
+		),
+	)
+
+	_, _ = Invoke[*testService](c)
+
+	ctx := context.Background()
+	_ = c.Start(ctx)
+	_ = c.Stop(ctx)
+
+	if stopped.Load() {
+		t.Error("a service that was never started should not be stopped")
+	}
+}
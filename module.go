@@ -2,6 +2,8 @@ package needle
 
 import (
 	"context"
+	reflectPkg "reflect"
+	"slices"
 
 	"github.com/danpasecinic/needle/internal/container"
 	"github.com/danpasecinic/needle/internal/reflect"
@@ -13,6 +15,11 @@ type Module struct {
 	decorators []decoratorEntry
 	bindings   []bindingEntry
 	submodules []*Module
+	exports    []string
+	requires   []any
+	onStart    []container.Hook
+	onStop     []container.Hook
+	namespace  string
 }
 
 type providerEntry struct {
@@ -62,14 +69,90 @@ func (m *Module) ProvideValue(value any, opts ...ProviderOption) *Module {
 	return m
 }
 
+func (m *Module) As(qualifier string) *Module {
+	namespace := qualifier
+	if m.namespace != "" {
+		namespace = qualifier + "." + m.namespace
+	}
+
+	name := m.name
+	if name != "" {
+		name += "." + qualifier
+	}
+
+	submodules := make([]*Module, len(m.submodules))
+	for i, sub := range m.submodules {
+		submodules[i] = sub.As(qualifier)
+	}
+
+	return &Module{
+		name:       name,
+		providers:  slices.Clone(m.providers),
+		decorators: slices.Clone(m.decorators),
+		bindings:   slices.Clone(m.bindings),
+		submodules: submodules,
+		exports:    slices.Clone(m.exports),
+		requires:   slices.Clone(m.requires),
+		onStart:    slices.Clone(m.onStart),
+		onStop:     slices.Clone(m.onStop),
+		namespace:  namespace,
+	}
+}
+
+func (m *Module) Namespace() string {
+	return m.namespace
+}
+
+func (m *Module) identity() string {
+	if m.name == "" || m.namespace == "" {
+		return m.name
+	}
+	return m.name + "@" + m.namespace
+}
+
+func (m *Module) OnStart(hook Hook) *Module {
+	m.onStart = append(m.onStart, container.Hook(hook))
+	return m
+}
+
+func (m *Module) OnStop(hook Hook) *Module {
+	m.onStop = append(m.onStop, container.Hook(hook))
+	return m
+}
+
 func (m *Module) Include(submodule *Module) *Module {
 	m.submodules = append(m.submodules, submodule)
 	return m
 }
 
-func (m *Module) apply(c *Container) error {
+func (m *Module) Requires(requirements ...any) *Module {
+	m.requires = append(m.requires, requirements...)
+	return m
+}
+
+func ModuleRequires[T any](m *Module) *Module {
+	return m.Requires(reflect.TypeKey[T]())
+}
+
+func (m *Module) apply(parent *Container, tx *applyTx) error {
+	if !tx.enter(parent, m) {
+		parent.config.logger.Debug("skipping module already applied", "module", m.name)
+		return nil
+	}
+
+	c := parent.enterModule(m)
+
+	if err := tx.require(c.scope.path, m.requires); err != nil {
+		return err
+	}
+
+	existing := make(map[string]bool)
+	for _, key := range c.internal.Keys() {
+		existing[key] = true
+	}
+
 	for _, sub := range m.submodules {
-		if err := sub.apply(c); err != nil {
+		if err := sub.apply(c, tx); err != nil {
 			return err
 		}
 	}
@@ -87,15 +170,33 @@ func (m *Module) apply(c *Container) error {
 	}
 
 	for _, d := range m.decorators {
-		c.internal.AddDecorator(
-			d.key, func(ctx context.Context, r container.Resolver, instance any) (any, error) {
-				resolver := &resolverAdapter{container: c}
-				return d.decorator(ctx, resolver, instance)
+		key := c.scopeKey(d.key)
+		tx.decorators = append(
+			tx.decorators, func() {
+				c.internal.AddDecorator(
+					key, func(ctx context.Context, r container.Resolver, instance any) (any, error) {
+						return d.decorator(ctx, c.resolver, instance)
+					},
+				)
 			},
 		)
 	}
 
-	return nil
+	var added []string
+	for _, key := range c.internal.Keys() {
+		if !existing[key] {
+			added = append(added, key)
+		}
+	}
+
+	if len(m.onStart) > 0 || len(m.onStop) > 0 {
+		if err := c.internal.AddModuleHooks(c.scope.path, added, m.onStart, m.onStop); err != nil {
+			return err
+		}
+		tx.hooks = append(tx.hooks, c.scope.path)
+	}
+
+	return c.sealModule(added)
 }
 
 func applyBinding(c *Container, b bindingEntry) error {
@@ -106,25 +207,15 @@ func applyBinding(c *Container, b bindingEntry) error {
 
 	key := b.interfaceKey
 	if cfg.name != "" {
-		key = cfg.name + "#" + b.interfaceKey
+		key = b.interfaceKey + "#" + cfg.name
 	}
+	key = c.localKey(key)
 
-	wrappedProvider := func(ctx context.Context, r container.Resolver) (any, error) {
-		return r.Resolve(ctx, b.implKey)
-	}
-
-	if err := c.internal.Register(key, wrappedProvider, []string{b.implKey}); err != nil {
-		return err
-	}
-
-	for _, hook := range cfg.onStart {
-		c.internal.AddOnStart(key, hook)
-	}
-	for _, hook := range cfg.onStop {
-		c.internal.AddOnStop(key, hook)
+	if !c.admit(key, []string{b.implKey}, cfg) {
+		return nil
 	}
 
-	return nil
+	return skipDefault(registerBinding(c, key, b.implKey, cfg))
 }
 
 func provideAny(c *Container, provider any, opts ...ProviderOption) error {
@@ -146,9 +237,14 @@ func provideValueAny(c *Container, value any, opts ...ProviderOption) error {
 	if cfg.name != "" {
 		key = reflect.TypeKeyNamedFromValue(value, cfg.name)
 	}
+	key = c.localKey(key)
 
-	if err := c.internal.RegisterValue(key, value); err != nil {
-		return err
+	if !c.admit(key, nil, cfg) {
+		return nil
+	}
+
+	if err := registerValue(c, key, value, cfg); err != nil {
+		return skipDefault(err)
 	}
 
 	for _, hook := range cfg.onStart {
@@ -157,16 +253,25 @@ func provideValueAny(c *Container, value any, opts ...ProviderOption) error {
 	for _, hook := range cfg.onStop {
 		c.internal.AddOnStop(key, hook)
 	}
+	applySelection(c, key, cfg)
+
+	if err := applyAliases(c, key, reflectPkg.TypeOf(value), cfg); err != nil {
+		c.internal.Unregister(key)
+		return err
+	}
 
 	return nil
 }
 
 func (c *Container) Apply(modules ...*Module) error {
+	tx := c.beginApply()
 	for _, m := range modules {
-		if err := m.apply(c); err != nil {
+		if err := m.apply(c, tx); err != nil {
+			tx.rollback()
 			return errModuleApplyFailed(m.name, err)
 		}
 	}
+	tx.commit()
 	return nil
 }
 
@@ -208,6 +313,28 @@ func ModuleProvideValue[T any](m *Module, value T, opts ...ProviderOption) *Modu
 	return m
 }
 
+func ModuleProvideFunc[T any](m *Module, constructor any, opts ...ProviderOption) *Module {
+	m.providers = append(
+		m.providers, providerEntry{
+			register: func(c *Container) error {
+				return ProvideFunc[T](c, constructor, opts...)
+			},
+		},
+	)
+	return m
+}
+
+func ModuleProvideStruct[T any](m *Module, opts ...ProviderOption) *Module {
+	m.providers = append(
+		m.providers, providerEntry{
+			register: func(c *Container) error {
+				return ProvideStruct[T](c, opts...)
+			},
+		},
+	)
+	return m
+}
+
 func ModuleBind[I, T any](m *Module, opts ...ProviderOption) *Module {
 	interfaceKey := reflect.TypeKey[I]()
 	implKey := reflect.TypeKey[T]()
@@ -240,3 +367,27 @@ func ModuleDecorate[T any](m *Module, decorator Decorator[T]) *Module {
 	)
 	return m
 }
+
+func ModuleBindNamed[I, T any](m *Module, name string, opts ...ProviderOption) *Module {
+	opts = append(opts, WithName(name))
+	return ModuleBind[I, T](m, opts...)
+}
+
+func ModuleDecorateNamed[T any](m *Module, name string, decorator Decorator[T]) *Module {
+	key := reflect.TypeKeyNamed[T](name)
+
+	m.decorators = append(
+		m.decorators, decoratorEntry{
+			key: key,
+			decorator: func(ctx context.Context, r Resolver, instance any) (any, error) {
+				typed, ok := instance.(T)
+				if !ok {
+					var zero T
+					return zero, errDecoratorTypeMismatch(reflect.TypeName[T]())
+				}
+				return decorator(ctx, r, typed)
+			},
+		},
+	)
+	return m
+}
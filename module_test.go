@@ -2,6 +2,9 @@ package needle_test
 
 import (
 	"context"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/danpasecinic/needle"
@@ -352,3 +355,298 @@ func TestMultipleModules(t *testing.T) {
 		t.Errorf("expected port 8080, got %d", db.Config.Port)
 	}
 }
+
+func TestModuleLifecycleHooks(t *testing.T) {
+	t.Parallel()
+
+	c := needle.New()
+	var events []string
+	record := func(event string) needle.Hook {
+		return func(ctx context.Context) error {
+			events = append(events, event)
+			return nil
+		}
+	}
+
+	module := needle.NewModule("storage").
+		OnStart(record("module:start")).
+		OnStop(record("module:stop"))
+	needle.ModuleProvideValue(
+		module, &Database{Name: "db"},
+		needle.WithOnStart(record("db:start")),
+		needle.WithOnStop(record("db:stop")),
+	)
+	needle.ModuleProvide(
+		module, func(ctx context.Context, r needle.Resolver) (*Logger, error) {
+			return &Logger{}, nil
+		},
+		needle.WithLazy(),
+	)
+
+	if err := c.Apply(module); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	for _, key := range c.Keys() {
+		if strings.HasPrefix(key, "module:") {
+			t.Errorf("module hooks should not be listed as services, got %v", c.Keys())
+		}
+	}
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := c.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+
+	expected := []string{"db:start", "module:start", "module:stop", "db:stop"}
+	if len(events) != len(expected) {
+		t.Fatalf("expected events %v, got %v", expected, events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Errorf("expected events %v, got %v", expected, events)
+			break
+		}
+	}
+
+	if needle.MustInvoke[*Logger](c) == nil {
+		t.Error("expected lazy service to remain resolvable")
+	}
+}
+
+func TestModuleLifecycleHooksParallel(t *testing.T) {
+	t.Parallel()
+
+	c := needle.New(needle.WithParallel())
+	var mu sync.Mutex
+	var events []string
+	record := func(event string) needle.Hook {
+		return func(ctx context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, event)
+			return nil
+		}
+	}
+
+	module := needle.NewModule("storage").
+		OnStart(record("module:start")).
+		OnStop(record("module:stop"))
+	needle.ModuleProvideValue(module, &Database{}, needle.WithOnStart(record("db:start")), needle.WithOnStop(record("db:stop")))
+	needle.ModuleProvideValue(module, &Logger{}, needle.WithOnStart(record("log:start")), needle.WithOnStop(record("log:stop")))
+
+	if err := c.Apply(module); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := c.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+
+	if len(events) != 6 || events[2] != "module:start" || events[3] != "module:stop" {
+		t.Errorf("expected module hooks between service hooks, got %v", events)
+	}
+	if slices.Contains(c.Keys(), "module:storage") {
+		t.Errorf("module hooks should not be listed as services, got %v", c.Keys())
+	}
+}
+
+func TestModuleParity(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"provide func and struct", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			module := needle.NewModule("users")
+			needle.ModuleProvideValue(module, &Database{Name: "users"})
+			needle.ModuleProvideFunc[*PostgresUserRepo](
+				module, func(db *Database) *PostgresUserRepo {
+					return &PostgresUserRepo{DB: db}
+				},
+			)
+			needle.ModuleProvideValue(module, &TestLogger{})
+			needle.ModuleProvideValue(module, &TestDatabase{})
+			needle.ModuleProvideStruct[*TestServiceWithTags](module)
+
+			if err := c.Apply(module); err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+			if err := c.Validate(); err != nil {
+				t.Fatalf("Validate failed: %v", err)
+			}
+
+			if needle.MustInvoke[*PostgresUserRepo](c).DB.Name != "users" {
+				t.Error("constructor dependency not injected")
+			}
+			if needle.MustInvoke[*TestServiceWithTags](c).Logger == nil {
+				t.Error("struct dependency not injected")
+			}
+		},
+	)
+
+	t.Run(
+		"bind and decorate named", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			module := needle.NewModule("repos")
+			needle.ModuleProvideValue(module, &Database{Name: "named"})
+			needle.ModuleProvide(
+				module, func(ctx context.Context, r needle.Resolver) (*PostgresUserRepo, error) {
+					return &PostgresUserRepo{DB: needle.MustInvoke[*Database](c)}, nil
+				},
+			)
+			needle.ModuleBindNamed[UserRepository, *PostgresUserRepo](module, "users")
+			needle.ModuleProvide(
+				module, func(ctx context.Context, r needle.Resolver) (*Logger, error) {
+					return &Logger{Prefix: "audit"}, nil
+				}, needle.WithName("audit"),
+			)
+			needle.ModuleDecorateNamed(
+				module, "audit", func(ctx context.Context, r needle.Resolver, base *Logger) (*Logger, error) {
+					base.Prefix = "[" + base.Prefix + "]"
+					return base, nil
+				},
+			)
+
+			if err := c.Apply(module); err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+
+			repo, err := needle.InvokeNamed[UserRepository](c, "users")
+			if err != nil {
+				t.Fatalf("InvokeNamed failed: %v", err)
+			}
+			if repo.FindByID(1) != "user-named" {
+				t.Errorf("unexpected repository result %q", repo.FindByID(1))
+			}
+			if got := needle.MustInvokeNamed[*Logger](c, "audit").Prefix; got != "[audit]" {
+				t.Errorf("expected named decorator to apply, got %q", got)
+			}
+		},
+	)
+}
+
+func databaseModule(port int) *needle.Module {
+	module := needle.NewModule("db")
+	needle.ModuleProvideValue(module, &Config{Port: port})
+	needle.ModuleProvideValue(module, &Logger{Prefix: "slow"}, needle.WithName("queries"))
+	needle.ModuleProvideFunc[*Database](
+		module, func(cfg *Config) *Database {
+			return &Database{Config: cfg, Name: "db"}
+		},
+	)
+	needle.ModuleDecorate(
+		module, func(ctx context.Context, r needle.Resolver, base *Database) (*Database, error) {
+			base.Name += "-decorated"
+			return base, nil
+		},
+	)
+	return module
+}
+
+func TestModuleNamespace(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"renames keys and internal references", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			replica := databaseModule(5433).As("replica")
+			if replica.Namespace() != "replica" {
+				t.Errorf("expected namespace replica, got %q", replica.Namespace())
+			}
+
+			if err := c.Apply(databaseModule(5432), replica); err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+			if err := c.Validate(); err != nil {
+				t.Fatalf("Validate failed: %v", err)
+			}
+
+			primary := needle.MustInvoke[*Database](c)
+			if primary.Config.Port != 5432 || primary.Name != "db-decorated" {
+				t.Errorf("unexpected primary database: port %d, name %q", primary.Config.Port, primary.Name)
+			}
+
+			secondary := needle.MustInvokeNamed[*Database](c, "replica")
+			if secondary.Config.Port != 5433 || secondary.Name != "db-decorated" {
+				t.Errorf("unexpected replica database: port %d, name %q", secondary.Config.Port, secondary.Name)
+			}
+
+			if !needle.HasNamed[*Logger](c, "replica.queries") {
+				t.Error("expected named key to be namespaced")
+			}
+
+			for _, svc := range c.Graph().Services {
+				if svc.Key != "*github.com/danpasecinic/needle_test.Database#replica" {
+					continue
+				}
+				if len(svc.Dependencies) != 1 || svc.Dependencies[0] != "*github.com/danpasecinic/needle_test.Config#replica" {
+					t.Errorf("expected rewritten dependency, got %v", svc.Dependencies)
+				}
+				if svc.Module != "db.replica" {
+					t.Errorf("expected module db.replica, got %q", svc.Module)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"applies each namespace once", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			module := databaseModule(5432)
+
+			if err := c.Apply(module.As("replica"), module.As("replica")); err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+			if !needle.HasNamed[*Database](c, "replica") || needle.Has[*Database](c) {
+				t.Errorf("unexpected keys %v", c.Keys())
+			}
+		},
+	)
+
+	t.Run(
+		"namespaces submodules", func(t *testing.T) {
+			t.Parallel()
+
+			storage := func(port int) *needle.Module {
+				config := needle.NewModule("config")
+				needle.ModuleProvideValue(config, &Config{Port: port})
+
+				module := needle.NewModule("db").Include(config)
+				needle.ModuleProvideFunc[*Database](
+					module, func(cfg *Config) *Database {
+						return &Database{Config: cfg}
+					},
+				)
+				return module
+			}
+
+			c := needle.New()
+			if err := c.Apply(storage(5432), storage(5433).As("replica")); err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+			if err := c.Validate(); err != nil {
+				t.Fatalf("Validate failed: %v", err)
+			}
+
+			if c.Size() != 4 || !needle.HasNamed[*Config](c, "replica") {
+				t.Errorf("expected the replica config to be namespaced, got keys %v", c.Keys())
+			}
+			if port := needle.MustInvoke[*Database](c).Config.Port; port != 5432 {
+				t.Errorf("expected primary port 5432, got %d", port)
+			}
+			if port := needle.MustInvokeNamed[*Database](c, "replica").Config.Port; port != 5433 {
+				t.Errorf("expected replica port 5433, got %d", port)
+			}
+		},
+	)
+}
@@ -2,6 +2,7 @@ package needle
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"time"
 )
@@ -14,6 +15,10 @@ type StartHook func(key string, duration time.Duration, err error)
 
 type StopHook func(key string, duration time.Duration, err error)
 
+type StateHook func(from, to State)
+
+type ReloadHook func(key string, duration time.Duration, err error)
+
 type HealthStatus string
 
 const (
@@ -27,6 +32,7 @@ type HealthReport struct {
 	Status  HealthStatus
 	Error   error
 	Latency time.Duration
+	Runner  *RunnerStatus
 }
 
 type HealthChecker interface {
@@ -105,7 +111,7 @@ func (c *Container) checkHealth(ctx context.Context) []HealthReport {
 	}
 
 	wg.Wait()
-	return reports
+	return append(reports, c.runnerReports()...)
 }
 
 func (c *Container) checkReadiness(ctx context.Context) []HealthReport {
@@ -152,5 +158,14 @@ func (c *Container) checkReadiness(ctx context.Context) []HealthReport {
 	}
 
 	wg.Wait()
+	if c.draining.Load() {
+		reports = append(
+			reports, HealthReport{
+				Name:   "container",
+				Status: HealthStatusDown,
+				Error:  errors.New("container is draining"),
+			},
+		)
+	}
 	return reports
 }
@@ -37,6 +37,24 @@ func WithStopObserver(hook StopHook) Option {
 	}
 }
 
+func WithStateObserver(hook StateHook) Option {
+	return func(cfg *containerConfig) {
+		cfg.onState = append(cfg.onState, hook)
+	}
+}
+
+func WithReloadObserver(hook ReloadHook) Option {
+	return func(cfg *containerConfig) {
+		cfg.onReload = append(cfg.onReload, hook)
+	}
+}
+
+func WithStartupTimeout(timeout time.Duration) Option {
+	return func(cfg *containerConfig) {
+		cfg.startupTimeout = timeout
+	}
+}
+
 func WithShutdownTimeout(timeout time.Duration) Option {
 	return func(cfg *containerConfig) {
 		cfg.shutdownTimeout = timeout
@@ -48,3 +66,33 @@ func WithParallel() Option {
 		cfg.parallel = true
 	}
 }
+
+func WithMaxConcurrency(n int) Option {
+	return func(cfg *containerConfig) {
+		cfg.maxConcurrency = n
+	}
+}
+
+func WithRestartStrategy(strategy RestartStrategy) Option {
+	return func(cfg *containerConfig) {
+		cfg.restartStrategy = strategy
+	}
+}
+
+func WithPhases(phases ...string) Option {
+	return func(cfg *containerConfig) {
+		cfg.phases = append(cfg.phases, phases...)
+	}
+}
+
+func WithProfiles(profiles ...string) Option {
+	return func(cfg *containerConfig) {
+		cfg.profiles = append(cfg.profiles, profiles...)
+	}
+}
+
+func WithProfileCombinations(combinations ...[]string) Option {
+	return func(cfg *containerConfig) {
+		cfg.combinations = append(cfg.combinations, combinations...)
+	}
+}
@@ -0,0 +1,151 @@
+package needle
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/danpasecinic/needle/internal/container"
+)
+
+type StartupPlan struct {
+	Parallel bool
+	Groups   []PlanGroup
+}
+
+type PlanGroup struct {
+	Level    int
+	Services []PlannedService
+}
+
+type PlannedService struct {
+	Key          string
+	Scope        string
+	Phase        string
+	Lazy         bool
+	Skip         string
+	Instantiated bool
+	OnStart      int
+	OnStop       int
+	StartTimeout time.Duration
+	StopTimeout  time.Duration
+	Runner       bool
+	After        []string
+}
+
+func (c *Container) Plan() (StartupPlan, error) {
+	g := c.internal.Graph()
+	entries := c.internal.Entries()
+	byKey := make(map[string]container.EntryInfo, len(entries))
+	for _, entry := range entries {
+		byKey[entry.Key] = entry
+	}
+
+	plan := StartupPlan{Parallel: c.config.parallel}
+
+	if plan.Parallel {
+		waves, err := c.internal.PlanStartup()
+		if err != nil {
+			return StartupPlan{}, err
+		}
+		for level, wave := range waves {
+			planned := PlanGroup{Level: level}
+			for _, scheduled := range wave {
+				svc := c.planService(scheduled.Key, byKey[scheduled.Key], g.GetPhase(scheduled.Key))
+				svc.After = scheduled.After
+				planned.Services = append(planned.Services, svc)
+			}
+			plan.Groups = append(plan.Groups, planned)
+		}
+		return plan, nil
+	}
+
+	order, err := g.StartupOrder()
+	if err != nil {
+		return StartupPlan{}, fmt.Errorf("failed to determine startup order: %w", err)
+	}
+	for i, key := range order {
+		plan.Groups = append(
+			plan.Groups, PlanGroup{
+				Level:    i,
+				Services: []PlannedService{c.planService(key, byKey[key], g.GetPhase(key))},
+			},
+		)
+	}
+	return plan, nil
+}
+
+func (c *Container) planService(key string, entry container.EntryInfo, phase string) PlannedService {
+	svc := PlannedService{
+		Key:          key,
+		Scope:        entry.Scope.String(),
+		Phase:        phase,
+		Lazy:         entry.Lazy,
+		Instantiated: entry.Instantiated,
+		OnStart:      entry.OnStart,
+		OnStop:       entry.OnStop,
+		StartTimeout: entry.StartTimeout,
+		StopTimeout:  entry.StopTimeout,
+	}
+
+	switch {
+	case entry.Lazy:
+		svc.Skip = "lazy"
+	case entry.Assisted:
+		svc.Skip = "assisted"
+	}
+
+	c.runners.mu.Lock()
+	_, svc.Runner = c.runners.specs[key]
+	c.runners.mu.Unlock()
+	if _, ok := entry.Instance.(Runnable); ok && entry.Binding == "" {
+		svc.Runner = true
+	}
+
+	return svc
+}
+
+func (c *Container) FprintPlan(w io.Writer) error {
+	plan, err := c.Plan()
+	if err != nil {
+		return err
+	}
+
+	mode := "sequential"
+	if plan.Parallel {
+		mode = "parallel"
+	}
+	_, _ = fmt.Fprintf(w, "Startup Plan (%s):\n", mode)
+
+	for _, group := range plan.Groups {
+		_, _ = fmt.Fprintf(w, "\n  [%d]\n", group.Level)
+		for _, svc := range group.Services {
+			_, _ = fmt.Fprintf(w, "    %s\n", planLine(svc))
+		}
+	}
+	return nil
+}
+
+func planLine(svc PlannedService) string {
+	details := []string{svc.Scope}
+	if svc.Phase != "" {
+		details = append(details, "phase="+svc.Phase)
+	}
+	if svc.OnStart > 0 || svc.OnStop > 0 {
+		details = append(details, fmt.Sprintf("hooks=%d/%d", svc.OnStart, svc.OnStop))
+	}
+	if svc.StartTimeout > 0 {
+		details = append(details, "start-timeout="+svc.StartTimeout.String())
+	}
+	if svc.StopTimeout > 0 {
+		details = append(details, "stop-timeout="+svc.StopTimeout.String())
+	}
+	if svc.Runner {
+		details = append(details, "runner")
+	}
+	if svc.Skip != "" {
+		details = append(details, "skip="+svc.Skip)
+	}
+	return fmt.Sprintf("%s (%s)", svc.Key, strings.Join(details, ", "))
+}
@@ -0,0 +1,183 @@
+package needle_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danpasecinic/needle"
+)
+
+type PlanCache struct{}
+
+func newPlanContainer(opts ...needle.Option) *needle.Container {
+	c := needle.New(opts...)
+	noop := func(ctx context.Context) error { return nil }
+
+	_ = needle.ProvideValue(c, &Config{Port: 8080}, needle.WithOnStart(noop))
+	_ = needle.ProvideValue(c, &PlanCache{})
+	_ = needle.ProvideFunc[*Database](
+		c, func(cfg *Config) *Database {
+			return &Database{Config: cfg}
+		},
+		needle.WithOnStart(noop),
+		needle.WithOnStop(noop),
+		needle.WithStopTimeout(time.Second),
+	)
+	_ = needle.ProvideFunc[*TestUserService](
+		c, func(db *Database) *TestUserService {
+			return &TestUserService{}
+		},
+		needle.WithLazy(),
+	)
+	return c
+}
+
+func TestPlan(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"sequential", func(t *testing.T) {
+			t.Parallel()
+
+			c := newPlanContainer()
+			plan, err := c.Plan()
+			if err != nil {
+				t.Fatalf("Plan failed: %v", err)
+			}
+
+			var keys []string
+			for _, group := range plan.Groups {
+				if len(group.Services) != 1 {
+					t.Fatalf("expected one service per step, got %d", len(group.Services))
+				}
+				keys = append(keys, group.Services[0].Key)
+			}
+			if len(keys) != 4 || !strings.HasSuffix(keys[0], ".Config") || !strings.HasSuffix(keys[2], ".PlanCache") {
+				t.Errorf("unexpected order: %v", keys)
+			}
+
+			db := plan.Groups[1].Services[0]
+			if db.OnStart != 1 || db.OnStop != 1 || db.StopTimeout != time.Second || db.Instantiated {
+				t.Errorf("unexpected database plan: %+v", db)
+			}
+			if user := plan.Groups[3].Services[0]; !user.Lazy || user.Skip != "lazy" {
+				t.Errorf("expected lazy service to be skipped: %+v", user)
+			}
+		},
+	)
+
+	t.Run(
+		"parallel", func(t *testing.T) {
+			t.Parallel()
+
+			c := newPlanContainer(needle.WithParallel())
+			plan, err := c.Plan()
+			if err != nil {
+				t.Fatalf("Plan failed: %v", err)
+			}
+			if !plan.Parallel || len(plan.Groups) != 3 || len(plan.Groups[0].Services) != 2 {
+				t.Errorf("unexpected parallel plan: %+v", plan)
+			}
+
+			var buf bytes.Buffer
+			if err := c.FprintPlan(&buf); err != nil {
+				t.Fatalf("FprintPlan failed: %v", err)
+			}
+			out := buf.String()
+			if !strings.Contains(out, "Startup Plan (parallel)") || !strings.Contains(out, "hooks=1/1, stop-timeout=1s") {
+				t.Errorf("unexpected plan output:\n%s", out)
+			}
+			if c.Size() != 4 || needle.MustInvoke[*Config](c) == nil {
+				t.Error("planning should not change the container")
+			}
+		},
+	)
+
+	t.Run(
+		"parallel follows the scheduler", func(t *testing.T) {
+			t.Parallel()
+
+			c := newPlanContainer(needle.WithParallel(), needle.WithMaxConcurrency(1))
+			plan, err := c.Plan()
+			if err != nil {
+				t.Fatalf("Plan failed: %v", err)
+			}
+
+			var keys []string
+			for _, group := range plan.Groups {
+				started := 0
+				for _, svc := range group.Services {
+					keys = append(keys, svc.Key)
+					if svc.Skip == "" {
+						started++
+					}
+				}
+				if started > 1 {
+					t.Errorf("expected at most one started service per group, got %+v", group)
+				}
+			}
+			if len(keys) != 4 || !strings.HasSuffix(keys[0], ".Config") || !strings.HasSuffix(keys[1], ".Database") {
+				t.Errorf("expected dispatch order of the scheduler, got %v", keys)
+			}
+
+			db := plan.Groups[1].Services[0]
+			if len(db.After) != 1 || !strings.HasSuffix(db.After[0], ".Config") {
+				t.Errorf("expected database to wait for config, got %v", db.After)
+			}
+		},
+	)
+}
+
+func TestTrace(t *testing.T) {
+	t.Parallel()
+
+	c := newPlanContainer(needle.WithParallel())
+	ctx := context.Background()
+	if err := c.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := c.Stop(ctx); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+
+	trace := c.Trace()
+	starts, stops, lanes := 0, 0, map[int]bool{}
+	for _, e := range trace.Events {
+		switch e.Phase {
+		case "start":
+			starts++
+			lanes[e.Lane] = true
+		case "stop":
+			stops++
+		}
+		if e.End.Before(e.Begin) {
+			t.Errorf("event ends before it begins: %+v", e)
+		}
+	}
+	if starts != 3 || stops != 3 || len(lanes) != 2 {
+		t.Errorf("unexpected trace: starts=%d stops=%d lanes=%v", starts, stops, lanes)
+	}
+
+	var buf bytes.Buffer
+	if err := trace.WriteChromeTrace(&buf); err != nil {
+		t.Fatalf("WriteChromeTrace failed: %v", err)
+	}
+	var exported struct {
+		TraceEvents []struct {
+			Name  string `json:"name"`
+			Cat   string `json:"cat"`
+			Phase string `json:"ph"`
+			TID   int    `json:"tid"`
+		} `json:"traceEvents"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &exported); err != nil {
+		t.Fatalf("invalid trace json: %v", err)
+	}
+	if len(exported.TraceEvents) != 6 || exported.TraceEvents[0].Phase != "X" {
+		t.Errorf("unexpected chrome trace: %s", buf.String())
+	}
+}
@@ -0,0 +1,235 @@
+package needle
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/danpasecinic/needle/internal/graph"
+)
+
+type profileState struct {
+	mu           sync.RWMutex
+	active       map[string]bool
+	combinations [][]string
+	rules        map[string][][]string
+	inactive     []inactiveRecord
+}
+
+type inactiveRecord struct {
+	key             string
+	dependencies    []string
+	profiles        [][]string
+	conditionFailed bool
+	reason          string
+}
+
+func newProfileState(profiles []string, combinations [][]string) *profileState {
+	active := make(map[string]bool, len(profiles))
+	for _, p := range profiles {
+		active[p] = true
+	}
+	return &profileState{
+		active:       active,
+		combinations: combinations,
+		rules:        make(map[string][][]string),
+	}
+}
+
+func WithProfile(profiles ...string) ProviderOption {
+	return func(cfg *providerConfig) {
+		cfg.profiles = append(cfg.profiles, profiles)
+	}
+}
+
+func WithCondition(condition func(*Container) bool) ProviderOption {
+	return func(cfg *providerConfig) {
+		cfg.conditions = append(cfg.conditions, condition)
+	}
+}
+
+func (c *Container) Profiles() []string {
+	c.profiles.mu.RLock()
+	defer c.profiles.mu.RUnlock()
+
+	profiles := make([]string, 0, len(c.profiles.active))
+	for p := range c.profiles.active {
+		profiles = append(profiles, p)
+	}
+	sort.Strings(profiles)
+	return profiles
+}
+
+func (c *Container) ProfileActive(profile string) bool {
+	c.profiles.mu.RLock()
+	defer c.profiles.mu.RUnlock()
+
+	return c.profiles.active[profile]
+}
+
+func (c *Container) ValidateProfiles(profiles ...string) error {
+	if err := c.validateProfiles(profiles); err != nil {
+		return errValidationFailed(err)
+	}
+	return nil
+}
+
+func (c *Container) admit(key string, dependencies []string, cfg *providerConfig) bool {
+	if len(cfg.profiles) == 0 && len(cfg.conditions) == 0 {
+		return true
+	}
+
+	reason := ""
+	conditionFailed := false
+	for _, cond := range cfg.conditions {
+		if !cond(c) {
+			reason = "condition not met"
+			conditionFailed = true
+			break
+		}
+	}
+
+	c.profiles.mu.Lock()
+	defer c.profiles.mu.Unlock()
+
+	if reason == "" && !profilesMatch(cfg.profiles, c.profiles.active) {
+		reason = "profile " + formatProfiles(cfg.profiles) + " not active"
+	}
+
+	if reason != "" {
+		c.profiles.inactive = append(
+			c.profiles.inactive, inactiveRecord{
+				key:             key,
+				dependencies:    dependencies,
+				profiles:        cfg.profiles,
+				conditionFailed: conditionFailed,
+				reason:          reason,
+			},
+		)
+		c.config.logger.Debug("skipping inactive provider", "service", key, "reason", reason)
+		return false
+	}
+
+	if len(cfg.profiles) > 0 {
+		c.profiles.rules[key] = cfg.profiles
+	}
+	return true
+}
+
+func (c *Container) validateProfiles(profiles []string) error {
+	active := make(map[string]bool, len(profiles))
+	for _, p := range profiles {
+		active[p] = true
+	}
+
+	live := c.internal.Graph()
+	simulated := graph.New()
+
+	c.profiles.mu.RLock()
+	for _, key := range live.Nodes() {
+		if rule, ok := c.profiles.rules[key]; ok && !profilesMatch(rule, active) {
+			continue
+		}
+		simulated.AddNode(key, live.GetDependencies(key))
+		simulated.SetDeferred(key, live.GetDeferred(key))
+	}
+
+	var duplicates []string
+	for _, record := range c.profiles.inactive {
+		if record.conditionFailed || !profilesMatch(record.profiles, active) {
+			continue
+		}
+		if simulated.HasNode(record.key) {
+			duplicates = append(duplicates, record.key)
+			continue
+		}
+		simulated.AddNode(record.key, record.dependencies)
+	}
+	c.profiles.mu.RUnlock()
+
+	name := "[" + strings.Join(profiles, ",") + "]"
+
+	if len(duplicates) > 0 {
+		sort.Strings(duplicates)
+		return fmt.Errorf("profile %s: duplicate services: %v", name, duplicates)
+	}
+
+	var missing []string
+	for _, key := range simulated.Validate() {
+		if !c.internal.Has(key) || live.HasNode(key) {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("profile %s: missing dependencies: %v", name, missing)
+	}
+
+	if simulated.HasCycle() {
+		return fmt.Errorf("profile %s: circular dependencies detected: %v", name, simulated.GetAllCyclePaths())
+	}
+
+	return nil
+}
+
+func (c *Container) validateCombinations() error {
+	for _, combination := range c.profiles.combinations {
+		if err := c.validateProfiles(combination); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Container) inactiveServices() []ServiceInfo {
+	c.profiles.mu.RLock()
+	defer c.profiles.mu.RUnlock()
+
+	services := make([]ServiceInfo, 0, len(c.profiles.inactive))
+	for _, record := range c.profiles.inactive {
+		services = append(
+			services, ServiceInfo{
+				Key:            record.key,
+				Dependencies:   slices.Clone(record.dependencies),
+				Inactive:       true,
+				InactiveReason: record.reason,
+			},
+		)
+	}
+	return services
+}
+
+func profilesMatch(groups [][]string, active map[string]bool) bool {
+	for _, group := range groups {
+		if !groupMatches(group, active) {
+			return false
+		}
+	}
+	return true
+}
+
+func groupMatches(group []string, active map[string]bool) bool {
+	if len(group) == 0 {
+		return true
+	}
+	for _, p := range group {
+		if negated, ok := strings.CutPrefix(p, "!"); ok {
+			if !active[negated] {
+				return true
+			}
+		} else if active[p] {
+			return true
+		}
+	}
+	return false
+}
+
+func formatProfiles(groups [][]string) string {
+	parts := make([]string, len(groups))
+	for i, group := range groups {
+		parts[i] = strings.Join(group, "|")
+	}
+	return "[" + strings.Join(parts, ",") + "]"
+}
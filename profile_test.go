@@ -0,0 +1,190 @@
+package needle_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/danpasecinic/needle"
+)
+
+type Mailer interface {
+	Send(to string) string
+}
+
+type SMTPMailer struct{}
+
+func (SMTPMailer) Send(to string) string { return "smtp:" + to }
+
+type LogMailer struct{}
+
+func (LogMailer) Send(to string) string { return "log:" + to }
+
+func TestProfiles(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"selects provider by active profile", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New(needle.WithProfiles("prod", "eu"))
+			_ = needle.ProvideValue[Mailer](c, SMTPMailer{}, needle.WithProfile("prod"))
+			_ = needle.ProvideValue[Mailer](c, LogMailer{}, needle.WithProfile("dev", "test"))
+
+			if needle.MustInvoke[Mailer](c).Send("a") != "smtp:a" {
+				t.Error("expected prod mailer")
+			}
+			if !c.ProfileActive("eu") || c.ProfileActive("dev") {
+				t.Errorf("unexpected active profiles: %v", c.Profiles())
+			}
+		},
+	)
+
+	t.Run(
+		"negated profiles", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New(needle.WithProfiles("dev"))
+			_ = needle.ProvideValue[Mailer](c, SMTPMailer{}, needle.WithProfile("!dev"))
+			_ = needle.ProvideValue[Mailer](c, LogMailer{}, needle.WithProfile("dev"))
+
+			if needle.MustInvoke[Mailer](c).Send("a") != "log:a" {
+				t.Error("expected dev mailer")
+			}
+		},
+	)
+
+	t.Run(
+		"profile groups combine with and", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New(needle.WithProfiles("prod"))
+			_ = needle.ProvideValue(c, &Config{}, needle.WithProfile("prod"), needle.WithProfile("eu"))
+
+			if needle.Has[*Config](c) {
+				t.Error("expected provider to require both profile groups")
+			}
+		},
+	)
+
+	t.Run(
+		"conditions", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.ProvideValue(c, &Config{Port: 1})
+			_ = needle.ProvideValue(
+				c, &Database{Name: "enabled"}, needle.WithCondition(
+					func(c *needle.Container) bool {
+						return needle.Has[*Config](c)
+					},
+				),
+			)
+			_ = needle.ProvideValue(
+				c, &Server{}, needle.WithCondition(
+					func(c *needle.Container) bool {
+						return false
+					},
+				),
+			)
+
+			if !needle.Has[*Database](c) {
+				t.Error("expected conditional provider to be registered")
+			}
+			if needle.Has[*Server](c) {
+				t.Error("expected conditional provider to be skipped")
+			}
+		},
+	)
+
+	t.Run(
+		"applies to bindings and modules", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New(needle.WithProfiles("prod"))
+			module := needle.NewModule("mail")
+			needle.ModuleProvideValue(module, &SMTPMailer{})
+			needle.ModuleProvideValue(module, &LogMailer{})
+			needle.ModuleBind[Mailer, *SMTPMailer](module, needle.WithProfile("prod"))
+			needle.ModuleBind[Mailer, *LogMailer](module, needle.WithProfile("dev"))
+
+			if err := c.Apply(module); err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+			if needle.MustInvoke[Mailer](c).Send("a") != "smtp:a" {
+				t.Error("expected prod binding")
+			}
+
+			_ = needle.Bind[Mailer, *LogMailer](c, needle.WithName("audit"), needle.WithProfile("dev"))
+			if needle.HasNamed[Mailer](c, "audit") {
+				t.Error("expected inactive binding to be skipped")
+			}
+		},
+	)
+}
+
+func TestInactiveProvidersInGraph(t *testing.T) {
+	t.Parallel()
+
+	c := needle.New(needle.WithProfiles("prod"))
+	_ = needle.ProvideValue[Mailer](c, SMTPMailer{}, needle.WithProfile("prod"))
+	_ = needle.ProvideValue[Mailer](c, LogMailer{}, needle.WithProfile("dev"))
+
+	var active, inactive int
+	for _, svc := range c.Graph().Services {
+		if svc.Inactive {
+			inactive++
+		} else {
+			active++
+		}
+	}
+	if active != 1 || inactive != 1 {
+		t.Errorf("expected 1 active and 1 inactive service, got %d and %d", active, inactive)
+	}
+
+	output := c.SprintGraph()
+	if !strings.Contains(output, "inactive: profile [dev] not active") {
+		t.Errorf("expected inactive marker, got: %s", output)
+	}
+	if strings.Count(c.SprintGraphDOT(), "Mailer\" [label") != 1 {
+		t.Errorf("expected inactive services to be omitted from DOT output")
+	}
+}
+
+func TestValidateProfiles(t *testing.T) {
+	t.Parallel()
+
+	newContainer := func(opts ...needle.Option) *needle.Container {
+		c := needle.New(opts...)
+		_ = needle.ProvideValue(c, &Config{}, needle.WithProfile("prod"))
+		_ = needle.ProvideValue[Mailer](c, SMTPMailer{}, needle.WithProfile("prod"))
+		_ = needle.ProvideValue[Mailer](c, LogMailer{}, needle.WithProfile("dev"))
+		_ = needle.Provide(
+			c, func(ctx context.Context, r needle.Resolver) (*Server, error) {
+				return &Server{}, nil
+			}, needle.WithDependencies("*github.com/danpasecinic/needle_test.Config"),
+		)
+		return c
+	}
+
+	c := newContainer(needle.WithProfiles("prod"))
+	if err := c.Validate(); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+	if err := c.ValidateProfiles("prod", "eu"); err != nil {
+		t.Errorf("unexpected error for prod: %v", err)
+	}
+
+	err := c.ValidateProfiles("dev")
+	if err == nil || !strings.Contains(err.Error(), "missing dependencies") {
+		t.Errorf("expected missing Config under dev, got %v", err)
+	}
+
+	c = newContainer(
+		needle.WithProfiles("prod"),
+		needle.WithProfileCombinations([]string{"prod"}, []string{"dev"}),
+	)
+	if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "profile [dev]") {
+		t.Errorf("expected Validate to check declared combinations, got %v", err)
+	}
+}
@@ -2,6 +2,10 @@ package needle
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	reflectPkg "reflect"
+	"time"
 
 	"github.com/danpasecinic/needle/internal/container"
 	"github.com/danpasecinic/needle/internal/reflect"
@@ -15,16 +19,32 @@ type ProviderOption func(*providerConfig)
 type providerConfig struct {
 	name         string
 	dependencies []string
+	deferred     []string
+	aliases      []reflectPkg.Type
 	onStart      []container.Hook
 	onStop       []container.Hook
 	scope        scope.Scope
 	poolSize     int
+	argCacheSize int
 	lazy         bool
+	primary      bool
+	priority     int
+	isDefault    bool
+	profiles     [][]string
+	conditions   []func(*Container) bool
+	retry        *RetryPolicy
+	timeout      time.Duration
+	startTimeout time.Duration
+	stopTimeout  time.Duration
+	run          func(ctx context.Context) error
+	restart      *RestartPolicy
+	phase        string
+	startAfter   []string
 }
 
 func Provide[T any](c *Container, provider Provider[T], opts ...ProviderOption) error {
 	if len(opts) == 0 {
-		key := reflect.TypeKey[T]()
+		key := c.localKey(reflect.TypeKey[T]())
 		resolver := c.resolver
 		wrappedProvider := func(ctx context.Context, r container.Resolver) (any, error) {
 			return provider(ctx, resolver)
@@ -41,31 +61,27 @@ func Provide[T any](c *Container, provider Provider[T], opts ...ProviderOption)
 	if cfg.name != "" {
 		key = reflect.TypeKeyNamed[T](cfg.name)
 	}
+	key = c.localKey(key)
+
+	if !c.admit(key, cfg.dependencies, cfg) {
+		return nil
+	}
 
 	resolver := c.resolver
 	wrappedProvider := func(ctx context.Context, r container.Resolver) (any, error) {
 		return provider(ctx, resolver)
 	}
+	wrappedProvider = c.withRetry(key, wrappedProvider, cfg)
 
-	if err := c.internal.Register(key, wrappedProvider, cfg.dependencies); err != nil {
-		return err
+	if err := registerProvider(c, key, wrappedProvider, cfg); err != nil {
+		return skipDefault(err)
 	}
 
-	for _, hook := range cfg.onStart {
-		c.internal.AddOnStart(key, hook)
-	}
-	for _, hook := range cfg.onStop {
-		c.internal.AddOnStop(key, hook)
-	}
-
-	if cfg.scope != scope.Singleton {
-		c.internal.SetScope(key, cfg.scope)
-	}
-	if cfg.poolSize > 0 {
-		c.internal.SetPoolSize(key, cfg.poolSize)
-	}
-	if cfg.lazy {
-		c.internal.SetLazy(key, true)
+	applyScope(c, key, cfg)
+	if err := applyProviderOptions(c, key, reflectPkg.TypeFor[T](), cfg); err != nil {
+		c.internal.Unregister(key)
+		forgetRunner(c, key)
+		return err
 	}
 
 	return nil
@@ -81,16 +97,20 @@ func ProvideValue[T any](c *Container, value T, opts ...ProviderOption) error {
 	if cfg.name != "" {
 		key = reflect.TypeKeyNamed[T](cfg.name)
 	}
+	key = c.localKey(key)
 
-	if err := c.internal.RegisterValue(key, value); err != nil {
-		return err
+	if !c.admit(key, nil, cfg) {
+		return nil
 	}
 
-	for _, hook := range cfg.onStart {
-		c.internal.AddOnStart(key, hook)
+	if err := registerValue(c, key, value, cfg); err != nil {
+		return skipDefault(err)
 	}
-	for _, hook := range cfg.onStop {
-		c.internal.AddOnStop(key, hook)
+
+	if err := applyProviderOptions(c, key, reflectPkg.TypeFor[T](), cfg); err != nil {
+		c.internal.Unregister(key)
+		forgetRunner(c, key)
+		return err
 	}
 
 	return nil
@@ -98,7 +118,7 @@ func ProvideValue[T any](c *Container, value T, opts ...ProviderOption) error {
 
 func ProvideNamed[T any](c *Container, name string, provider Provider[T], opts ...ProviderOption) error {
 	if len(opts) == 0 {
-		key := reflect.TypeKeyNamed[T](name)
+		key := c.localKey(reflect.TypeKeyNamed[T](name))
 		resolver := c.resolver
 		wrappedProvider := func(ctx context.Context, r container.Resolver) (any, error) {
 			return provider(ctx, resolver)
@@ -109,6 +129,16 @@ func ProvideNamed[T any](c *Container, name string, provider Provider[T], opts .
 	return Provide(c, provider, opts...)
 }
 
+func ProvideDefault[T any](c *Container, provider Provider[T], opts ...ProviderOption) error {
+	opts = append(opts, WithDefault())
+	return Provide(c, provider, opts...)
+}
+
+func ProvideDefaultValue[T any](c *Container, value T, opts ...ProviderOption) error {
+	opts = append(opts, WithDefault())
+	return ProvideValue(c, value, opts...)
+}
+
 func ProvideNamedValue[T any](c *Container, name string, value T, opts ...ProviderOption) error {
 	opts = append(opts, WithName(name))
 	return ProvideValue(c, value, opts...)
@@ -126,6 +156,12 @@ func WithDependencies(deps ...string) ProviderOption {
 	}
 }
 
+func withDeferredDependencies(deps ...string) ProviderOption {
+	return func(cfg *providerConfig) {
+		cfg.deferred = deps
+	}
+}
+
 func WithOnStart(hook Hook) ProviderOption {
 	return func(cfg *providerConfig) {
 		cfg.onStart = append(cfg.onStart, container.Hook(hook))
@@ -151,8 +187,170 @@ func WithPoolSize(size int) ProviderOption {
 	}
 }
 
+func WithArgCache(size int) ProviderOption {
+	return func(cfg *providerConfig) {
+		cfg.argCacheSize = size
+	}
+}
+
+func WithPhase(phase string) ProviderOption {
+	return func(cfg *providerConfig) {
+		cfg.phase = phase
+	}
+}
+
+func WithStartAfter(keys ...string) ProviderOption {
+	return func(cfg *providerConfig) {
+		cfg.startAfter = append(cfg.startAfter, keys...)
+	}
+}
+
+func WithStartTimeout(timeout time.Duration) ProviderOption {
+	return func(cfg *providerConfig) {
+		cfg.startTimeout = timeout
+	}
+}
+
+func WithStopTimeout(timeout time.Duration) ProviderOption {
+	return func(cfg *providerConfig) {
+		cfg.stopTimeout = timeout
+	}
+}
+
+func WithPrimary() ProviderOption {
+	return func(cfg *providerConfig) {
+		cfg.primary = true
+	}
+}
+
+func WithPriority(priority int) ProviderOption {
+	return func(cfg *providerConfig) {
+		cfg.priority = priority
+	}
+}
+
+func WithDefault() ProviderOption {
+	return func(cfg *providerConfig) {
+		cfg.isDefault = true
+	}
+}
+
 func WithLazy() ProviderOption {
 	return func(cfg *providerConfig) {
 		cfg.lazy = true
 	}
 }
+
+func applyProviderOptions(c *Container, key string, implType reflectPkg.Type, cfg *providerConfig) error {
+	for _, hook := range cfg.onStart {
+		c.internal.AddOnStart(key, hook)
+	}
+	for _, hook := range cfg.onStop {
+		c.internal.AddOnStop(key, hook)
+	}
+	applyTimeouts(c, key, cfg)
+	applyOrdering(c, key, cfg)
+	applyRunner(c, key, cfg)
+	applySelection(c, key, cfg)
+	return applyAliases(c, key, implType, cfg)
+}
+
+func applyScope(c *Container, key string, cfg *providerConfig) {
+	if cfg.scope != scope.Singleton {
+		c.internal.SetScope(key, cfg.scope)
+	}
+	if cfg.poolSize > 0 {
+		c.internal.SetPoolSize(key, cfg.poolSize)
+	}
+	if cfg.lazy {
+		c.internal.SetLazy(key, true)
+	}
+	if len(cfg.deferred) > 0 {
+		c.internal.SetDeferred(key, cfg.deferred)
+	}
+}
+
+func applySelection(c *Container, key string, cfg *providerConfig) {
+	if cfg.primary {
+		c.internal.SetPrimary(key, true)
+	}
+	if cfg.priority != 0 {
+		c.internal.SetPriority(key, cfg.priority)
+	}
+}
+
+func applyTimeouts(c *Container, key string, cfg *providerConfig) {
+	if cfg.startTimeout > 0 {
+		c.internal.SetStartTimeout(key, cfg.startTimeout)
+	}
+	if cfg.stopTimeout > 0 {
+		c.internal.SetStopTimeout(key, cfg.stopTimeout)
+	}
+}
+
+func applyOrdering(c *Container, key string, cfg *providerConfig) {
+	if cfg.phase != "" {
+		c.internal.SetPhase(key, cfg.phase)
+	}
+	if len(cfg.startAfter) > 0 {
+		after := make([]string, len(cfg.startAfter))
+		for i, k := range cfg.startAfter {
+			after[i] = c.scopeKey(k)
+		}
+		c.internal.SetStartAfter(key, after)
+	}
+}
+
+var providerOptionSet = map[string]func(cfg *providerConfig) bool{
+	"WithName":         func(cfg *providerConfig) bool { return cfg.name != "" },
+	"WithPrimary":      func(cfg *providerConfig) bool { return cfg.primary },
+	"WithPriority":     func(cfg *providerConfig) bool { return cfg.priority != 0 },
+	"WithOnStart":      func(cfg *providerConfig) bool { return len(cfg.onStart) > 0 },
+	"WithOnStop":       func(cfg *providerConfig) bool { return len(cfg.onStop) > 0 },
+	"WithScope":        func(cfg *providerConfig) bool { return cfg.scope != scope.Singleton },
+	"WithPoolSize":     func(cfg *providerConfig) bool { return cfg.poolSize > 0 },
+	"WithArgCache":     func(cfg *providerConfig) bool { return cfg.argCacheSize > 0 },
+	"WithLazy":         func(cfg *providerConfig) bool { return cfg.lazy },
+	"WithDefault":      func(cfg *providerConfig) bool { return cfg.isDefault },
+	"WithProfile":      func(cfg *providerConfig) bool { return len(cfg.profiles) > 0 },
+	"WithCondition":    func(cfg *providerConfig) bool { return len(cfg.conditions) > 0 },
+	"As":               func(cfg *providerConfig) bool { return len(cfg.aliases) > 0 },
+	"WithRetry":        func(cfg *providerConfig) bool { return cfg.retry != nil },
+	"WithTimeout":      func(cfg *providerConfig) bool { return cfg.timeout > 0 },
+	"WithRun":          func(cfg *providerConfig) bool { return cfg.run != nil },
+	"WithRestart":      func(cfg *providerConfig) bool { return cfg.restart != nil },
+	"WithPhase":        func(cfg *providerConfig) bool { return cfg.phase != "" },
+	"WithStartAfter":   func(cfg *providerConfig) bool { return len(cfg.startAfter) > 0 },
+	"WithStartTimeout": func(cfg *providerConfig) bool { return cfg.startTimeout > 0 },
+	"WithStopTimeout":  func(cfg *providerConfig) bool { return cfg.stopTimeout > 0 },
+}
+
+func rejectOptions(target string, cfg *providerConfig, unsupported ...string) error {
+	for _, option := range unsupported {
+		if providerOptionSet[option](cfg) {
+			return fmt.Errorf("%s does not support %s", target, option)
+		}
+	}
+	return nil
+}
+
+func registerProvider(c *Container, key string, provider container.ProviderFunc, cfg *providerConfig) error {
+	if cfg.isDefault {
+		return c.internal.RegisterDefault(key, provider, cfg.dependencies)
+	}
+	return c.internal.Register(key, provider, cfg.dependencies)
+}
+
+func registerValue(c *Container, key string, value any, cfg *providerConfig) error {
+	if cfg.isDefault {
+		return c.internal.RegisterValueDefault(key, value)
+	}
+	return c.internal.RegisterValue(key, value)
+}
+
+func skipDefault(err error) error {
+	if errors.Is(err, container.ErrDefaultSkipped) {
+		return nil
+	}
+	return err
+}
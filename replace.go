@@ -9,11 +9,16 @@ import (
 	"github.com/danpasecinic/needle/internal/reflect"
 )
 
+var replaceUnsupported = []string{"WithDefault", "WithProfile", "WithCondition", "WithArgCache"}
+
 func Replace[T any](c *Container, provider Provider[T], opts ...ProviderOption) error {
 	cfg := &providerConfig{}
 	for _, opt := range opts {
 		opt(cfg)
 	}
+	if err := rejectOptions("Replace", cfg, replaceUnsupported...); err != nil {
+		return err
+	}
 
 	key := reflect.TypeKey[T]()
 	if cfg.name != "" {
@@ -24,29 +29,15 @@ func Replace[T any](c *Container, provider Provider[T], opts ...ProviderOption)
 		resolver := &resolverAdapter{container: c}
 		return provider(ctx, resolver)
 	}
+	wrappedProvider = c.withRetry(key, wrappedProvider, cfg)
 
 	if err := c.internal.Replace(key, wrappedProvider, cfg.dependencies); err != nil {
 		return err
 	}
+	forgetRunner(c, key)
 
-	for _, hook := range cfg.onStart {
-		c.internal.AddOnStart(key, hook)
-	}
-	for _, hook := range cfg.onStop {
-		c.internal.AddOnStop(key, hook)
-	}
-
-	if cfg.scope != 0 {
-		c.internal.SetScope(key, cfg.scope)
-	}
-	if cfg.poolSize > 0 {
-		c.internal.SetPoolSize(key, cfg.poolSize)
-	}
-	if cfg.lazy {
-		c.internal.SetLazy(key, true)
-	}
-
-	return nil
+	applyScope(c, key, cfg)
+	return applyProviderOptions(c, key, reflectPkg.TypeFor[T](), cfg)
 }
 
 func ReplaceValue[T any](c *Container, value T, opts ...ProviderOption) error {
@@ -54,6 +45,12 @@ func ReplaceValue[T any](c *Container, value T, opts ...ProviderOption) error {
 	for _, opt := range opts {
 		opt(cfg)
 	}
+	unsupported := append(
+		[]string{"WithScope", "WithPoolSize", "WithLazy", "WithRetry", "WithTimeout"}, replaceUnsupported...,
+	)
+	if err := rejectOptions("ReplaceValue", cfg, unsupported...); err != nil {
+		return err
+	}
 
 	key := reflect.TypeKey[T]()
 	if cfg.name != "" {
@@ -63,15 +60,9 @@ func ReplaceValue[T any](c *Container, value T, opts ...ProviderOption) error {
 	if err := c.internal.ReplaceValue(key, value); err != nil {
 		return err
 	}
+	forgetRunner(c, key)
 
-	for _, hook := range cfg.onStart {
-		c.internal.AddOnStart(key, hook)
-	}
-	for _, hook := range cfg.onStop {
-		c.internal.AddOnStop(key, hook)
-	}
-
-	return nil
+	return applyProviderOptions(c, key, reflectPkg.TypeFor[T](), cfg)
 }
 
 func ReplaceNamed[T any](c *Container, name string, provider Provider[T], opts ...ProviderOption) error {
@@ -112,21 +103,14 @@ func ReplaceFunc[T any](c *Container, constructor any, opts ...ProviderOption) e
 	hasError := fnType.NumOut() == 2 &&
 		fnType.Out(1).Implements(reflectPkg.TypeOf((*error)(nil)).Elem())
 
-	deps := make([]string, len(params))
-	for i, p := range params {
-		deps[i] = p.TypeKey
-	}
+	deps, deferred := funcDependencies(params)
 
 	provider := func(ctx context.Context, r Resolver) (T, error) {
 		var zero T
 
-		args := make([]reflectPkg.Value, len(params))
-		for i, p := range params {
-			instance, err := c.internal.Resolve(ctx, p.TypeKey)
-			if err != nil {
-				return zero, fmt.Errorf("failed to resolve parameter %d (%s): %w", i, p.TypeKey, err)
-			}
-			args[i] = reflectPkg.ValueOf(instance)
+		args, err := resolveFuncArgs(ctx, c, params)
+		if err != nil {
+			return zero, err
 		}
 
 		results := fnVal.Call(args)
@@ -138,7 +122,7 @@ func ReplaceFunc[T any](c *Container, constructor any, opts ...ProviderOption) e
 		return results[0].Interface().(T), nil
 	}
 
-	opts = append([]ProviderOption{WithDependencies(deps...)}, opts...)
+	opts = append([]ProviderOption{WithDependencies(deps...), withDeferredDependencies(deferred...)}, opts...)
 	return Replace(c, provider, opts...)
 }
 
@@ -153,19 +137,9 @@ func ReplaceStruct[T any](c *Container, opts ...ProviderOption) error {
 		return InvokeStructCtx[T](ctx, c)
 	}
 
-	fields, _ := reflect.StructFields[T](TagKey)
-	deps := make([]string, 0, len(fields))
-	for _, f := range fields {
-		if !f.Optional {
-			if f.Named != "" {
-				deps = append(deps, f.TypeKey+"#"+f.Named)
-			} else {
-				deps = append(deps, f.TypeKey)
-			}
-		}
-	}
+	deps, deferred := structDependencies[T]()
 
-	opts = append([]ProviderOption{WithDependencies(deps...)}, opts...)
+	opts = append([]ProviderOption{WithDependencies(deps...), withDeferredDependencies(deferred...)}, opts...)
 	return Replace(c, provider, opts...)
 }
 
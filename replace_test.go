@@ -2,7 +2,10 @@ package needle_test
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/danpasecinic/needle"
 )
@@ -159,6 +162,104 @@ func TestReplaceWithOptions(t *testing.T) {
 			}
 		},
 	)
+
+	t.Run(
+		"applies retry and aliases", func(t *testing.T) {
+			c := needle.New()
+
+			_ = needle.ProvideValue(c, &MemoryStore{Name: "old"}, needle.As[Store]())
+
+			attempts := 0
+			err := needle.Replace(
+				c, func(ctx context.Context, r needle.Resolver) (*MemoryStore, error) {
+					attempts++
+					if attempts < 2 {
+						return nil, errors.New("not yet")
+					}
+					return &MemoryStore{Name: "new"}, nil
+				},
+				needle.WithRetry(needle.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}),
+				needle.As[Store](),
+			)
+			if err != nil {
+				t.Fatalf("Replace failed: %v", err)
+			}
+
+			store := needle.MustInvoke[Store](c)
+			if store.Get("k") != "new:k" {
+				t.Errorf("expected replaced store, got %s", store.Get("k"))
+			}
+			if attempts != 2 {
+				t.Errorf("expected 2 attempts, got %d", attempts)
+			}
+		},
+	)
+
+	t.Run(
+		"replaces the runner", func(t *testing.T) {
+			c := needle.New()
+
+			_ = needle.ProvideValue(
+				c, &ReplaceConfig{Value: "old"}, needle.WithRun(
+					func(ctx context.Context) error {
+						<-ctx.Done()
+						return nil
+					},
+				),
+			)
+
+			ran := make(chan struct{})
+			err := needle.ReplaceValue(
+				c, &ReplaceConfig{Value: "new"}, needle.WithRun(
+					func(ctx context.Context) error {
+						close(ran)
+						<-ctx.Done()
+						return nil
+					},
+				),
+			)
+			if err != nil {
+				t.Fatalf("ReplaceValue failed: %v", err)
+			}
+
+			if err := c.Start(context.Background()); err != nil {
+				t.Fatalf("Start failed: %v", err)
+			}
+			defer func() { _ = c.Stop(context.Background()) }()
+
+			select {
+			case <-ran:
+			case <-time.After(time.Second):
+				t.Fatal("expected the replacement runner to run")
+			}
+		},
+	)
+
+	t.Run(
+		"rejects unsupported options", func(t *testing.T) {
+			c := needle.New()
+
+			_ = needle.ProvideValue(c, &ReplaceConfig{Value: "old"})
+
+			err := needle.ReplaceValue(
+				c, &ReplaceConfig{Value: "new"},
+				needle.WithRetry(needle.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}),
+			)
+			if err == nil || !strings.Contains(err.Error(), "WithRetry") {
+				t.Errorf("expected WithRetry to be rejected, got %v", err)
+			}
+
+			err = needle.Replace(
+				c, func(ctx context.Context, r needle.Resolver) (*ReplaceConfig, error) {
+					return &ReplaceConfig{}, nil
+				},
+				needle.WithDefault(),
+			)
+			if err == nil || !strings.Contains(err.Error(), "WithDefault") {
+				t.Errorf("expected WithDefault to be rejected, got %v", err)
+			}
+		},
+	)
 }
 
 func NewReplaceService(cfg *ReplaceConfig) *ReplaceService {
@@ -16,11 +16,11 @@ type resolverAdapter struct {
 }
 
 func (r *resolverAdapter) Resolve(ctx context.Context, key string) (any, error) {
-	return r.container.internal.Resolve(ctx, key)
+	return r.container.internal.Resolve(ctx, r.container.scopeKey(key))
 }
 
 func (r *resolverAdapter) Has(key string) bool {
-	return r.container.internal.Has(key)
+	return r.container.internal.Has(r.container.scopeKey(key))
 }
 
 func Invoke[T any](c *Container) (T, error) {
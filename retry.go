@@ -0,0 +1,139 @@
+package needle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/danpasecinic/needle/internal/container"
+)
+
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	Jitter         float64
+	Retryable      func(error) bool
+}
+
+type RetryError struct {
+	Attempts []error
+}
+
+func (e *RetryError) Error() string {
+	parts := make([]string, len(e.Attempts))
+	for i, err := range e.Attempts {
+		parts[i] = fmt.Sprintf("attempt %d: %v", i+1, err)
+	}
+	return fmt.Sprintf("%d attempts failed: %s", len(e.Attempts), strings.Join(parts, "; "))
+}
+
+func (e *RetryError) Unwrap() []error {
+	return e.Attempts
+}
+
+func WithRetry(policy RetryPolicy) ProviderOption {
+	return func(cfg *providerConfig) {
+		cfg.retry = &policy
+	}
+}
+
+func WithTimeout(timeout time.Duration) ProviderOption {
+	return func(cfg *providerConfig) {
+		cfg.timeout = timeout
+	}
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = 100 * time.Millisecond
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = 10 * time.Second
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = 2
+	}
+	return p
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := float64(p.InitialBackoff)
+	for i := 1; i < attempt; i++ {
+		delay *= p.Multiplier
+		if delay >= float64(p.MaxBackoff) {
+			delay = float64(p.MaxBackoff)
+			break
+		}
+	}
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(delay)
+}
+
+func (c *Container) withRetry(key string, provider container.ProviderFunc, cfg *providerConfig) container.ProviderFunc {
+	if cfg.retry == nil && cfg.timeout <= 0 {
+		return provider
+	}
+
+	timeout := cfg.timeout
+	policy := RetryPolicy{MaxAttempts: 1}
+	if cfg.retry != nil {
+		policy = cfg.retry.withDefaults()
+	}
+
+	attempt := func(ctx context.Context, r container.Resolver) (any, error) {
+		if timeout <= 0 {
+			return provider(ctx, r)
+		}
+		attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		instance, err := provider(attemptCtx, r)
+		if err != nil && ctx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+			return nil, errTimeout(key, timeout, err)
+		}
+		return instance, err
+	}
+
+	if policy.MaxAttempts == 1 {
+		return attempt
+	}
+
+	return func(ctx context.Context, r container.Resolver) (any, error) {
+		var attempts []error
+		for n := 1; ; n++ {
+			start := time.Now()
+			instance, err := attempt(ctx, r)
+			if err == nil {
+				return instance, nil
+			}
+			attempts = append(attempts, err)
+
+			if n >= policy.MaxAttempts || ctx.Err() != nil ||
+				(policy.Retryable != nil && !policy.Retryable(err)) {
+				return nil, errProviderFailed(key, &RetryError{Attempts: attempts})
+			}
+
+			for _, hook := range c.config.onResolve {
+				hook(key, time.Since(start), err)
+			}
+			c.config.logger.Debug("retrying provider", "service", key, "attempt", n, "error", err)
+
+			timer := time.NewTimer(policy.backoff(n))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, errProviderFailed(key, &RetryError{Attempts: attempts})
+			case <-timer.C:
+			}
+		}
+	}
+}
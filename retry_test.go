@@ -0,0 +1,166 @@
+package needle_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/danpasecinic/needle"
+)
+
+var errTransient = errors.New("connection refused")
+
+func TestProviderRetry(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"succeeds after transient failures", func(t *testing.T) {
+			t.Parallel()
+
+			var mu sync.Mutex
+			var attempts []error
+			c := needle.New(
+				needle.WithResolveObserver(
+					func(key string, duration time.Duration, err error) {
+						mu.Lock()
+						defer mu.Unlock()
+						attempts = append(attempts, err)
+					},
+				),
+			)
+
+			var calls atomic.Int32
+			_ = needle.Provide(
+				c, func(ctx context.Context, r needle.Resolver) (*Database, error) {
+					if calls.Add(1) < 3 {
+						return nil, errTransient
+					}
+					return &Database{}, nil
+				},
+				needle.WithRetry(needle.RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Millisecond, Jitter: 0.5}),
+			)
+
+			if err := c.Start(context.Background()); err != nil {
+				t.Fatalf("Start failed: %v", err)
+			}
+			if calls.Load() != 3 {
+				t.Errorf("expected 3 attempts, got %d", calls.Load())
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if len(attempts) != 3 || !errors.Is(attempts[0], errTransient) || attempts[2] != nil {
+				t.Errorf("expected each attempt to be observed, got %v", attempts)
+			}
+		},
+	)
+
+	t.Run(
+		"records every attempt", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.Provide(
+				c, func(ctx context.Context, r needle.Resolver) (*Database, error) {
+					return nil, errTransient
+				},
+				needle.WithRetry(needle.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}),
+			)
+
+			_, err := needle.Invoke[*Database](c)
+			var retryErr *needle.RetryError
+			if !errors.As(err, &retryErr) {
+				t.Fatalf("expected RetryError, got %v", err)
+			}
+			if len(retryErr.Attempts) != 3 {
+				t.Errorf("expected 3 recorded attempts, got %d", len(retryErr.Attempts))
+			}
+			if !needle.IsProviderFailed(err) || !errors.Is(err, errTransient) {
+				t.Errorf("expected provider error wrapping the cause, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"stops on non-retryable errors", func(t *testing.T) {
+			t.Parallel()
+
+			fatal := errors.New("invalid credentials")
+			var calls atomic.Int32
+			c := needle.New()
+			_ = needle.Provide(
+				c, func(ctx context.Context, r needle.Resolver) (*Database, error) {
+					calls.Add(1)
+					return nil, fatal
+				},
+				needle.WithRetry(
+					needle.RetryPolicy{
+						MaxAttempts:    5,
+						InitialBackoff: time.Millisecond,
+						Retryable: func(err error) bool {
+							return !errors.Is(err, fatal)
+						},
+					},
+				),
+			)
+
+			if _, err := needle.Invoke[*Database](c); err == nil {
+				t.Fatal("expected error")
+			}
+			if calls.Load() != 1 {
+				t.Errorf("expected a single attempt, got %d", calls.Load())
+			}
+		},
+	)
+}
+
+func TestProviderTimeout(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"cancels the provider context", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.Provide(
+				c, func(ctx context.Context, r needle.Resolver) (*Database, error) {
+					<-ctx.Done()
+					return nil, ctx.Err()
+				},
+				needle.WithTimeout(10*time.Millisecond),
+			)
+
+			_, err := needle.Invoke[*Database](c)
+			if !needle.IsTimeout(err) {
+				t.Errorf("expected timeout error, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"applies to each attempt", func(t *testing.T) {
+			t.Parallel()
+
+			var calls atomic.Int32
+			c := needle.New()
+			_ = needle.Provide(
+				c, func(ctx context.Context, r needle.Resolver) (*Database, error) {
+					if calls.Add(1) == 1 {
+						<-ctx.Done()
+						return nil, ctx.Err()
+					}
+					return &Database{}, nil
+				},
+				needle.WithTimeout(10*time.Millisecond),
+				needle.WithRetry(needle.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}),
+			)
+
+			if _, err := needle.Invoke[*Database](c); err != nil {
+				t.Errorf("expected second attempt to succeed, got %v", err)
+			}
+		},
+	)
+}
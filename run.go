@@ -0,0 +1,129 @@
+package needle
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+)
+
+type RunOptions struct {
+	Signals     []os.Signal
+	DrainPeriod time.Duration
+	OnReload    func(ctx context.Context) error
+}
+
+func (c *Container) Run(ctx context.Context) error {
+	return c.RunWithOptions(ctx, RunOptions{})
+}
+
+func (c *Container) RunWithOptions(ctx context.Context, opts RunOptions) error {
+	signals := opts.Signals
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+	}
+	if opts.OnReload != nil && slices.Contains(signals, os.Signal(syscall.SIGHUP)) {
+		return errors.New("SIGHUP cannot both stop the container and trigger OnReload")
+	}
+
+	quit := make(chan os.Signal, 2)
+	signal.Notify(quit, signals...)
+	defer signal.Stop(quit)
+
+	var reload chan os.Signal
+	if opts.OnReload != nil {
+		reload = make(chan os.Signal, 1)
+		signal.Notify(reload, syscall.SIGHUP)
+		defer signal.Stop(reload)
+	}
+
+	if err := c.Start(ctx); err != nil {
+		return err
+	}
+
+	escalated := c.waitForShutdown(ctx, quit, reload, opts.OnReload)
+
+	stopCtx, force := context.WithCancel(context.WithoutCancel(ctx))
+	defer force()
+
+	forcedBy := make(chan os.Signal, 1)
+	go func() {
+		select {
+		case sig := <-quit:
+			c.config.logger.Warn("second signal received, forcing shutdown", "signal", sig)
+			forcedBy <- sig
+			force()
+		case <-stopCtx.Done():
+		}
+	}()
+
+	if opts.DrainPeriod > 0 {
+		c.draining.Store(true)
+		c.config.logger.Info("draining before shutdown", "period", opts.DrainPeriod)
+		timer := time.NewTimer(opts.DrainPeriod)
+		select {
+		case <-timer.C:
+		case <-stopCtx.Done():
+			timer.Stop()
+		}
+	}
+
+	err := c.Stop(stopCtx)
+	c.draining.Store(false)
+
+	select {
+	case sig := <-forcedBy:
+		err = errShutdownForced(sig, err)
+	default:
+	}
+	return errors.Join(escalated, err)
+}
+
+func (c *Container) waitForShutdown(
+	ctx context.Context, quit, reload <-chan os.Signal, onReload func(ctx context.Context) error,
+) error {
+	c.runners.mu.Lock()
+	c.runners.waiting = true
+	select {
+	case <-c.runners.escalated:
+	default:
+	}
+	c.runners.mu.Unlock()
+	defer func() {
+		c.runners.mu.Lock()
+		c.runners.waiting = false
+		c.runners.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case sig := <-quit:
+			c.config.logger.Info("signal received, shutting down", "signal", sig)
+			return nil
+		case err := <-c.runners.escalated:
+			return err
+		case <-reload:
+			if err := onReload(ctx); err != nil {
+				c.config.logger.Error("reload failed", "error", err)
+			}
+		}
+	}
+}
+
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if sig, ok := forcingSignal(err); ok {
+		if num, ok := sig.(syscall.Signal); ok {
+			return 128 + int(num)
+		}
+		return 128 + int(syscall.SIGINT)
+	}
+	return 1
+}
@@ -0,0 +1,225 @@
+package needle_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/danpasecinic/needle"
+)
+
+func newRunContainer(t *testing.T, onStop needle.Hook) (*needle.Container, <-chan struct{}) {
+	t.Helper()
+
+	running := make(chan struct{})
+	c := needle.New()
+	_ = needle.ProvideValue(
+		c, &Database{},
+		needle.WithRun(
+			func(ctx context.Context) error {
+				close(running)
+				<-ctx.Done()
+				return nil
+			},
+		),
+		needle.WithOnStop(onStop),
+	)
+	return c, running
+}
+
+func sendSignal(t *testing.T, sig os.Signal) {
+	t.Helper()
+
+	process, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatalf("FindProcess failed: %v", err)
+	}
+	if err := process.Signal(sig); err != nil {
+		t.Fatalf("Signal failed: %v", err)
+	}
+}
+
+func TestRunWithOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"stops on a configured signal", func(t *testing.T) {
+			var stopped atomic.Bool
+			c, running := newRunContainer(
+				t, func(ctx context.Context) error {
+					stopped.Store(true)
+					return nil
+				},
+			)
+
+			done := make(chan error, 1)
+			go func() {
+				done <- c.RunWithOptions(context.Background(), needle.RunOptions{Signals: []os.Signal{syscall.SIGUSR1}})
+			}()
+
+			<-running
+			sendSignal(t, syscall.SIGUSR1)
+
+			if err := <-done; err != nil || needle.ExitCode(err) != 0 {
+				t.Fatalf("expected clean exit, got %v", err)
+			}
+			if !stopped.Load() {
+				t.Error("expected service to be stopped")
+			}
+		},
+	)
+
+	t.Run(
+		"drains before stopping", func(t *testing.T) {
+			var stopped atomic.Bool
+			c, running := newRunContainer(
+				t, func(ctx context.Context) error {
+					stopped.Store(true)
+					return nil
+				},
+			)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error, 1)
+			go func() {
+				done <- c.RunWithOptions(ctx, needle.RunOptions{DrainPeriod: 100 * time.Millisecond})
+			}()
+
+			<-running
+			if err := c.Ready(context.Background()); err != nil {
+				t.Fatalf("expected ready before shutdown, got %v", err)
+			}
+
+			begin := time.Now()
+			cancel()
+			waitUntil(
+				t, func() bool {
+					return c.Ready(context.Background()) != nil
+				},
+			)
+			if stopped.Load() {
+				t.Error("services should not stop while draining")
+			}
+
+			if err := <-done; err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if elapsed := time.Since(begin); elapsed < 100*time.Millisecond {
+				t.Errorf("expected drain period to elapse, took %v", elapsed)
+			}
+			if !stopped.Load() {
+				t.Error("expected service to be stopped after draining")
+			}
+		},
+	)
+
+	t.Run(
+		"second signal forces shutdown", func(t *testing.T) {
+			stopping := make(chan struct{})
+			c, running := newRunContainer(
+				t, func(ctx context.Context) error {
+					close(stopping)
+					<-ctx.Done()
+					return ctx.Err()
+				},
+			)
+
+			done := make(chan error, 1)
+			go func() {
+				done <- c.RunWithOptions(context.Background(), needle.RunOptions{Signals: []os.Signal{syscall.SIGUSR2}})
+			}()
+
+			<-running
+			sendSignal(t, syscall.SIGUSR2)
+			<-stopping
+			sendSignal(t, syscall.SIGUSR2)
+
+			err := <-done
+			if !needle.IsShutdownForced(err) {
+				t.Fatalf("expected forced shutdown, got %v", err)
+			}
+			var shutdownErr *needle.ShutdownError
+			if !errors.As(err, &shutdownErr) || len(shutdownErr.Failures) != 1 {
+				t.Errorf("expected the interrupted hook to be reported, got %v", err)
+			}
+			if code := needle.ExitCode(err); code != 128+int(syscall.SIGUSR2) {
+				t.Errorf("expected exit code %d, got %d", 128+int(syscall.SIGUSR2), code)
+			}
+		},
+	)
+
+	t.Run(
+		"reloads on SIGHUP", func(t *testing.T) {
+			c, running := newRunContainer(
+				t, func(ctx context.Context) error {
+					return nil
+				},
+			)
+
+			var reloads atomic.Int32
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error, 1)
+			go func() {
+				done <- c.RunWithOptions(
+					ctx, needle.RunOptions{
+						OnReload: func(ctx context.Context) error {
+							reloads.Add(1)
+							return errors.New("reload is logged, not fatal")
+						},
+					},
+				)
+			}()
+
+			<-running
+			sendSignal(t, syscall.SIGHUP)
+			waitUntil(
+				t, func() bool {
+					return reloads.Load() == 1
+				},
+			)
+
+			cancel()
+			if err := <-done; err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"rejects SIGHUP as a shutdown signal with OnReload", func(t *testing.T) {
+			c, _ := newRunContainer(
+				t, func(ctx context.Context) error {
+					return nil
+				},
+			)
+
+			err := c.RunWithOptions(
+				context.Background(), needle.RunOptions{
+					Signals:  []os.Signal{syscall.SIGTERM, syscall.SIGHUP},
+					OnReload: func(ctx context.Context) error { return nil },
+				},
+			)
+			if err == nil {
+				t.Fatal("expected an error for conflicting SIGHUP handling")
+			}
+			if c.State() != needle.StateNew {
+				t.Errorf("expected the container not to start, got %s", c.State())
+			}
+		},
+	)
+}
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+
+	if code := needle.ExitCode(nil); code != 0 {
+		t.Errorf("expected 0 for nil, got %d", code)
+	}
+	if code := needle.ExitCode(errors.New("boom")); code != 1 {
+		t.Errorf("expected 1 for a failure, got %d", code)
+	}
+}
@@ -0,0 +1,249 @@
+package needle_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/danpasecinic/needle"
+)
+
+type Cache struct {
+	Backend string
+}
+
+func provideCache(backend string) needle.Provider[*Cache] {
+	return func(ctx context.Context, r needle.Resolver) (*Cache, error) {
+		return &Cache{Backend: backend}, nil
+	}
+}
+
+func TestWithPrimary(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"unnamed resolution picks primary", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.ProvideNamed(c, "redis", provideCache("redis"), needle.WithPrimary())
+			_ = needle.ProvideNamed(c, "memory", provideCache("memory"))
+
+			cache, err := needle.Invoke[*Cache](c)
+			if err != nil {
+				t.Fatalf("Invoke failed: %v", err)
+			}
+			if cache.Backend != "redis" {
+				t.Errorf("expected redis, got %s", cache.Backend)
+			}
+			if cache != needle.MustInvokeNamed[*Cache](c, "redis") {
+				t.Error("expected primary to share the named singleton")
+			}
+		},
+	)
+
+	t.Run(
+		"unnamed registration takes precedence", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.Provide(c, provideCache("default"))
+			_ = needle.ProvideNamed(c, "redis", provideCache("redis"), needle.WithPrimary())
+
+			if needle.MustInvoke[*Cache](c).Backend != "default" {
+				t.Error("expected unnamed registration to win")
+			}
+		},
+	)
+
+	t.Run(
+		"fails without designated candidate", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.ProvideNamed(c, "redis", provideCache("redis"))
+			_ = needle.ProvideNamed(c, "memory", provideCache("memory"))
+
+			if _, err := needle.Invoke[*Cache](c); err == nil {
+				t.Error("expected error without primary")
+			}
+			if needle.Has[*Cache](c) {
+				t.Error("expected Has to be false without primary")
+			}
+		},
+	)
+
+	t.Run(
+		"validate reports conflicting primaries", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.ProvideNamed(c, "redis", provideCache("redis"), needle.WithPrimary())
+			_ = needle.ProvideNamed(c, "memory", provideCache("memory"), needle.WithPrimary())
+
+			err := c.Validate()
+			if err == nil || !strings.Contains(err.Error(), "several primary providers") {
+				t.Errorf("expected several primary providers error, got %v", err)
+			}
+			if _, err := needle.Invoke[*Cache](c); err == nil {
+				t.Error("expected resolution to fail with conflicting primaries")
+			}
+		},
+	)
+
+	t.Run(
+		"validate accepts dependencies satisfied by a primary", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.ProvideNamed(c, "redis", provideCache("redis"), needle.WithPrimary())
+			_ = needle.ProvideNamed(c, "memory", provideCache("memory"))
+			_ = needle.ProvideFunc[*Config](
+				c, func(cache *Cache) *Config {
+					return &Config{Host: cache.Backend}
+				},
+			)
+
+			if err := c.Validate(); err != nil {
+				t.Errorf("unexpected validation error: %v", err)
+			}
+
+			_ = needle.ProvideNamed(c, "disk", provideCache("disk"), needle.WithPrimary())
+			err := c.Validate()
+			if err == nil || !strings.Contains(err.Error(), "several primary providers") {
+				t.Errorf("expected several primary providers error, got %v", err)
+			}
+		},
+	)
+}
+
+func TestWithPriority(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"highest priority wins", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.ProvideNamed(c, "redis", provideCache("redis"), needle.WithPriority(10))
+			_ = needle.ProvideNamed(c, "memory", provideCache("memory"), needle.WithPriority(5))
+
+			if needle.MustInvoke[*Cache](c).Backend != "redis" {
+				t.Error("expected highest priority candidate")
+			}
+		},
+	)
+
+	t.Run(
+		"primary beats priority", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.ProvideNamed(c, "redis", provideCache("redis"), needle.WithPriority(10))
+			_ = needle.ProvideNamed(c, "memory", provideCache("memory"), needle.WithPrimary())
+
+			if needle.MustInvoke[*Cache](c).Backend != "memory" {
+				t.Error("expected primary candidate")
+			}
+		},
+	)
+
+	t.Run(
+		"priority breaks ties between primaries", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.ProvideNamed(c, "redis", provideCache("redis"), needle.WithPrimary(), needle.WithPriority(1))
+			_ = needle.ProvideNamed(c, "memory", provideCache("memory"), needle.WithPrimary())
+
+			if err := c.Validate(); err != nil {
+				t.Errorf("unexpected validation error: %v", err)
+			}
+			if needle.MustInvoke[*Cache](c).Backend != "redis" {
+				t.Error("expected higher priority primary")
+			}
+		},
+	)
+
+	t.Run(
+		"reports priority ties", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.ProvideNamed(c, "redis", provideCache("redis"), needle.WithPriority(5))
+			_ = needle.ProvideNamed(c, "memory", provideCache("memory"), needle.WithPriority(5))
+
+			err := c.Validate()
+			if err == nil || !strings.Contains(err.Error(), "priority 5 tied") || strings.Contains(err.Error(), "primary") {
+				t.Errorf("expected a priority tie error, got %v", err)
+			}
+		},
+	)
+}
+
+type AuditLog struct {
+	Cache *Cache
+}
+
+func TestSelectionLifecycleOrder(t *testing.T) {
+	t.Parallel()
+
+	var events []string
+	record := func(event string) needle.Hook {
+		return func(ctx context.Context) error {
+			events = append(events, event)
+			return nil
+		}
+	}
+
+	c := needle.New()
+	_ = needle.ProvideNamed(
+		c, "redis", provideCache("redis"), needle.WithPrimary(),
+		needle.WithOnStart(record("cache:start")), needle.WithOnStop(record("cache:stop")),
+	)
+	_ = needle.ProvideFunc[*AuditLog](
+		c, func(cache *Cache) *AuditLog {
+			return &AuditLog{Cache: cache}
+		},
+		needle.WithOnStart(record("audit:start")), needle.WithOnStop(record("audit:stop")),
+	)
+
+	ctx := context.Background()
+	if err := c.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := c.Stop(ctx); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+
+	expected := []string{"cache:start", "audit:start", "audit:stop", "cache:stop"}
+	if !slices.Equal(events, expected) {
+		t.Errorf("expected the selected provider around its dependents %v, got %v", expected, events)
+	}
+}
+
+func TestSelectionInGraph(t *testing.T) {
+	t.Parallel()
+
+	c := needle.New()
+	_ = needle.ProvideNamed(c, "redis", provideCache("redis"), needle.WithPrimary())
+	_ = needle.ProvideNamed(c, "memory", provideCache("memory"))
+
+	info := c.Graph()
+	var winner string
+	for _, svc := range info.Services {
+		if svc.Selected {
+			winner = svc.Key
+		}
+	}
+	if !strings.HasSuffix(winner, "#redis") {
+		t.Errorf("expected redis to be selected, got %q", winner)
+	}
+	if len(info.Selections) != 1 {
+		t.Errorf("expected 1 selection, got %v", info.Selections)
+	}
+	if !strings.Contains(c.SprintGraph(), "#redis ★") {
+		t.Errorf("expected winner marker, got: %s", c.SprintGraph())
+	}
+}
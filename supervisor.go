@@ -0,0 +1,337 @@
+package needle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+type Runnable interface {
+	Run(ctx context.Context) error
+}
+
+type RestartMode int
+
+const (
+	RestartNever RestartMode = iota
+	RestartOnFailure
+	RestartAlways
+)
+
+type RestartPolicy struct {
+	Mode           RestartMode
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	MaxFailures    int
+}
+
+type RunnerState string
+
+const (
+	RunnerRunning   RunnerState = "running"
+	RunnerBackoff   RunnerState = "backoff"
+	RunnerCompleted RunnerState = "completed"
+	RunnerFailed    RunnerState = "failed"
+	RunnerStopped   RunnerState = "stopped"
+)
+
+type RunnerStatus struct {
+	State     RunnerState
+	Restarts  int
+	Failures  int
+	LastError error
+}
+
+func WithRun(run func(ctx context.Context) error) ProviderOption {
+	return func(cfg *providerConfig) {
+		cfg.run = run
+	}
+}
+
+func WithRestart(policy RestartPolicy) ProviderOption {
+	return func(cfg *providerConfig) {
+		cfg.restart = &policy
+	}
+}
+
+type runnerSpec struct {
+	run    func(ctx context.Context) error
+	policy RestartPolicy
+}
+
+type runnerState struct {
+	mu        sync.Mutex
+	specs     map[string]runnerSpec
+	active    map[string]*supervisor
+	waiting   bool
+	escalated chan error
+}
+
+func newRunnerState() *runnerState {
+	return &runnerState{
+		specs:     make(map[string]runnerSpec),
+		active:    make(map[string]*supervisor),
+		escalated: make(chan error, 1),
+	}
+}
+
+type supervisor struct {
+	key    string
+	run    func(ctx context.Context) error
+	policy RestartPolicy
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu     sync.Mutex
+	status RunnerStatus
+}
+
+func applyRunner(c *Container, key string, cfg *providerConfig) {
+	if cfg.run == nil && cfg.restart == nil {
+		return
+	}
+
+	spec := runnerSpec{run: cfg.run}
+	if cfg.restart != nil {
+		spec.policy = *cfg.restart
+	}
+
+	c.runners.mu.Lock()
+	c.runners.specs[key] = spec
+	c.runners.mu.Unlock()
+}
+
+func forgetRunner(c *Container, key string) {
+	c.runners.mu.Lock()
+	delete(c.runners.specs, key)
+	c.runners.mu.Unlock()
+}
+
+func (c *Container) startRunners(ctx context.Context) error {
+	order, err := c.internal.Graph().StartupOrder()
+	if err != nil {
+		return err
+	}
+	entries := c.internal.Entries()
+	byKey := make(map[string]int, len(entries))
+	for i, entry := range entries {
+		byKey[entry.Key] = i
+	}
+
+	c.runners.mu.Lock()
+	defer c.runners.mu.Unlock()
+
+	base := context.WithoutCancel(ctx)
+	for _, key := range order {
+		i, ok := byKey[key]
+		if !ok || !entries[i].Instantiated || entries[i].Binding != "" {
+			continue
+		}
+
+		spec := c.runners.specs[key]
+		run := spec.run
+		if run == nil {
+			runnable, ok := entries[i].Instance.(Runnable)
+			if !ok {
+				continue
+			}
+			run = runnable.Run
+		}
+
+		runCtx, cancel := context.WithCancel(base)
+		s := &supervisor{
+			key:    key,
+			run:    run,
+			policy: spec.policy,
+			cancel: cancel,
+			done:   make(chan struct{}),
+			status: RunnerStatus{State: RunnerRunning},
+		}
+		c.runners.active[key] = s
+		go c.supervise(runCtx, s)
+	}
+
+	return nil
+}
+
+func (c *Container) supervise(ctx context.Context, s *supervisor) {
+	defer close(s.done)
+
+	consecutive := 0
+	for {
+		err := runSafely(ctx, s.run)
+		if ctx.Err() != nil {
+			s.setState(RunnerStopped, nil)
+			return
+		}
+
+		if err != nil {
+			consecutive++
+			s.mu.Lock()
+			s.status.Failures++
+			s.status.LastError = err
+			failures := s.status.Failures
+			s.mu.Unlock()
+
+			c.config.logger.Warn("runner failed", "service", s.key, "failures", failures, "error", err)
+
+			if s.policy.MaxFailures > 0 && failures >= s.policy.MaxFailures {
+				s.setState(RunnerFailed, err)
+				c.escalate(errRunnerFailed(s.key, failures, err))
+				return
+			}
+			if s.policy.Mode == RestartNever {
+				s.setState(RunnerFailed, err)
+				return
+			}
+		} else {
+			consecutive = 0
+			if s.policy.Mode != RestartAlways {
+				s.setState(RunnerCompleted, nil)
+				return
+			}
+		}
+
+		s.setState(RunnerBackoff, err)
+		timer := time.NewTimer(s.policy.backoff(consecutive))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			s.setState(RunnerStopped, nil)
+			return
+		case <-timer.C:
+		}
+
+		s.mu.Lock()
+		s.status.State = RunnerRunning
+		s.status.Restarts++
+		s.mu.Unlock()
+	}
+}
+
+func runSafely(ctx context.Context, run func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("runner panicked: %v", r)
+		}
+	}()
+	return run(ctx)
+}
+
+func (p RestartPolicy) backoff(consecutive int) time.Duration {
+	delay := p.InitialBackoff
+	if delay <= 0 {
+		delay = 100 * time.Millisecond
+	}
+	limit := p.MaxBackoff
+	if limit <= 0 {
+		limit = 30 * time.Second
+	}
+	for i := 1; i < consecutive && delay < limit; i++ {
+		delay *= 2
+	}
+	return min(delay, limit)
+}
+
+func (s *supervisor) setState(state RunnerState, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.status.State = state
+	if err != nil {
+		s.status.LastError = err
+	}
+}
+
+func (s *supervisor) snapshot() RunnerStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+func (c *Container) escalate(err error) {
+	c.config.logger.Error("runner escalated, stopping container", "error", err)
+
+	c.runners.mu.Lock()
+	waiting := c.runners.waiting
+	c.runners.mu.Unlock()
+
+	if waiting {
+		select {
+		case c.runners.escalated <- err:
+		default:
+		}
+		return
+	}
+
+	go func() {
+		_ = c.Stop(context.Background())
+	}()
+}
+
+func (c *Container) stopRunners(ctx context.Context) []*Error {
+	order := c.internal.StopSequence()
+
+	c.runners.mu.Lock()
+	active := c.runners.active
+	c.runners.active = make(map[string]*supervisor)
+	c.runners.mu.Unlock()
+
+	keys := make([]string, 0, len(active))
+	for _, key := range order {
+		if _, ok := active[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	remaining := make([]string, 0, len(active)-len(keys))
+	for key := range active {
+		if !slices.Contains(keys, key) {
+			remaining = append(remaining, key)
+		}
+	}
+	sort.Strings(remaining)
+
+	var errs []*Error
+	for _, key := range append(keys, remaining...) {
+		s := active[key]
+		s.cancel()
+		select {
+		case <-s.done:
+		case <-ctx.Done():
+			stopErr := errShutdownFailed(key, fmt.Errorf("runner did not stop: %w", ctx.Err()))
+			stopErr.Hook = -1
+			errs = append(errs, stopErr)
+		}
+	}
+	return errs
+}
+
+func (c *Container) runnerReports() []HealthReport {
+	c.runners.mu.Lock()
+	defer c.runners.mu.Unlock()
+
+	reports := make([]HealthReport, 0, len(c.runners.active))
+	for key, s := range c.runners.active {
+		status := s.snapshot()
+		report := HealthReport{Name: key, Runner: &status}
+		switch status.State {
+		case RunnerRunning, RunnerCompleted:
+			report.Status = HealthStatusUp
+		case RunnerBackoff, RunnerFailed:
+			report.Status = HealthStatusDown
+			report.Error = status.LastError
+			if report.Error == nil {
+				report.Error = errors.New("runner is restarting")
+			}
+		default:
+			report.Status = HealthStatusUnknown
+		}
+		reports = append(reports, report)
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Name < reports[j].Name })
+	return reports
+}
@@ -0,0 +1,217 @@
+package needle_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/danpasecinic/needle"
+)
+
+type Consumer struct {
+	name    string
+	started chan struct{}
+	mu      *sync.Mutex
+	stopped *[]string
+}
+
+func (w *Consumer) Run(ctx context.Context) error {
+	close(w.started)
+	<-ctx.Done()
+	w.mu.Lock()
+	*w.stopped = append(*w.stopped, w.name)
+	w.mu.Unlock()
+	return nil
+}
+
+type Scheduler struct {
+	*Consumer
+}
+
+func runnerStatus(c *needle.Container, key string) *needle.RunnerStatus {
+	for _, report := range c.Health(context.Background()) {
+		if report.Name == key && report.Runner != nil {
+			return report.Runner
+		}
+	}
+	return nil
+}
+
+func waitUntil(t *testing.T, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestRunnable(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var stopped []string
+	consumer := &Consumer{name: "consumer", started: make(chan struct{}), mu: &mu, stopped: &stopped}
+	scheduler := &Scheduler{&Consumer{name: "scheduler", started: make(chan struct{}), mu: &mu, stopped: &stopped}}
+
+	c := needle.New()
+	_ = needle.ProvideValue(c, consumer)
+	_ = needle.ProvideFunc[*Scheduler](
+		c, func(c *Consumer) *Scheduler {
+			return scheduler
+		},
+	)
+
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	<-consumer.started
+	<-scheduler.started
+
+	if status := runnerStatus(c, "*github.com/danpasecinic/needle_test.Consumer"); status == nil ||
+		status.State != needle.RunnerRunning {
+		t.Errorf("expected running consumer, got %+v", status)
+	}
+
+	if err := c.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(stopped) != 2 || stopped[0] != "scheduler" || stopped[1] != "consumer" {
+		t.Errorf("expected runners to stop in reverse dependency order, got %v", stopped)
+	}
+}
+
+func TestLazyRunnable(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var stopped []string
+	consumer := &Consumer{name: "consumer", started: make(chan struct{}), mu: &mu, stopped: &stopped}
+
+	c := needle.New()
+	_ = needle.ProvideFunc[*Consumer](
+		c, func() *Consumer {
+			return consumer
+		},
+		needle.WithLazy(),
+	)
+	if _, err := needle.Invoke[*Consumer](c); err != nil {
+		t.Fatalf("Invoke failed: %v", err)
+	}
+
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	<-consumer.started
+
+	if err := c.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(stopped) != 1 || stopped[0] != "consumer" {
+		t.Errorf("expected the lazy runner to stop, got %v", stopped)
+	}
+}
+
+func TestRunnerRestart(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"restarts on failure", func(t *testing.T) {
+			t.Parallel()
+
+			var runs atomic.Int32
+			c := needle.New()
+			_ = needle.ProvideValue(
+				c, &Database{},
+				needle.WithRun(
+					func(ctx context.Context) error {
+						if runs.Add(1) < 3 {
+							return errors.New("lost connection")
+						}
+						<-ctx.Done()
+						return nil
+					},
+				),
+				needle.WithRestart(needle.RestartPolicy{Mode: needle.RestartOnFailure, InitialBackoff: time.Millisecond}),
+			)
+
+			_ = c.Start(context.Background())
+			defer func() { _ = c.Stop(context.Background()) }()
+
+			key := "*github.com/danpasecinic/needle_test.Database"
+			waitUntil(
+				t, func() bool {
+					status := runnerStatus(c, key)
+					return status != nil && status.State == needle.RunnerRunning && status.Restarts == 2
+				},
+			)
+			if status := runnerStatus(c, key); status.Failures != 2 || status.LastError == nil {
+				t.Errorf("unexpected status: %+v", status)
+			}
+		},
+	)
+
+	t.Run(
+		"never restarts by default", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.ProvideValue(
+				c, &Database{},
+				needle.WithRun(
+					func(ctx context.Context) error {
+						return errors.New("boom")
+					},
+				),
+			)
+
+			_ = c.Start(context.Background())
+			defer func() { _ = c.Stop(context.Background()) }()
+
+			waitUntil(
+				t, func() bool {
+					return c.Live(context.Background()) != nil
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"escalates after max failures", func(t *testing.T) {
+			t.Parallel()
+
+			c := needle.New()
+			_ = needle.ProvideValue(
+				c, &Database{},
+				needle.WithRun(
+					func(ctx context.Context) error {
+						panic("corrupted state")
+					},
+				),
+				needle.WithRestart(
+					needle.RestartPolicy{Mode: needle.RestartAlways, InitialBackoff: time.Millisecond, MaxFailures: 3},
+				),
+			)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			err := c.Run(ctx)
+			if !needle.IsRunnerFailed(err) {
+				t.Errorf("expected runner escalation error, got %v", err)
+			}
+		},
+	)
+}
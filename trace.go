@@ -0,0 +1,85 @@
+package needle
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+type TraceEvent struct {
+	Service string
+	Phase   string
+	Lane    int
+	Begin   time.Time
+	End     time.Time
+	Error   error
+}
+
+type Trace struct {
+	Events []TraceEvent
+}
+
+func (c *Container) Trace() Trace {
+	events := c.internal.Trace()
+	trace := Trace{Events: make([]TraceEvent, len(events))}
+	for i, e := range events {
+		trace.Events[i] = TraceEvent{
+			Service: e.Key,
+			Phase:   e.Phase,
+			Lane:    e.Lane,
+			Begin:   e.Begin,
+			End:     e.End,
+			Error:   e.Err,
+		}
+	}
+	return trace
+}
+
+type chromeEvent struct {
+	Name      string            `json:"name"`
+	Category  string            `json:"cat"`
+	Phase     string            `json:"ph"`
+	Timestamp int64             `json:"ts"`
+	Duration  int64             `json:"dur"`
+	Process   int               `json:"pid"`
+	Thread    int               `json:"tid"`
+	Args      map[string]string `json:"args,omitempty"`
+}
+
+type chromeTrace struct {
+	TraceEvents     []chromeEvent `json:"traceEvents"`
+	DisplayTimeUnit string        `json:"displayTimeUnit"`
+}
+
+func (t Trace) WriteChromeTrace(w io.Writer) error {
+	var origin time.Time
+	for _, e := range t.Events {
+		if origin.IsZero() || e.Begin.Before(origin) {
+			origin = e.Begin
+		}
+	}
+
+	out := chromeTrace{
+		TraceEvents:     make([]chromeEvent, 0, len(t.Events)),
+		DisplayTimeUnit: "ms",
+	}
+	for _, e := range t.Events {
+		event := chromeEvent{
+			Name:      e.Service,
+			Category:  e.Phase,
+			Phase:     "X",
+			Timestamp: e.Begin.Sub(origin).Microseconds(),
+			Duration:  e.End.Sub(e.Begin).Microseconds(),
+			Process:   1,
+			Thread:    e.Lane,
+		}
+		if e.Error != nil {
+			event.Args = map[string]string{"error": e.Error.Error()}
+		}
+		out.TraceEvents = append(out.TraceEvents, event)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}